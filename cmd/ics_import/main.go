@@ -0,0 +1,100 @@
+// Command ics_import records the events of an iCalendar file as time entries of a user,
+// like POST /task/import. The database is taken from the config at CONFIG_PATH.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"time_tracker/internal/config"
+	"time_tracker/internal/icsimport"
+	"time_tracker/internal/storage/post"
+)
+
+func main() {
+	var (
+		userId       = flag.Int("user", 0, "id of the user the entries are created for")
+		file         = flag.String("file", "", "path of the .ics file, - for stdin")
+		category     = flag.String("category", "", "import the events with the category only")
+		keyword      = flag.String("keyword", "", "import the events with the keyword in the summary or the description only")
+		projectId    = flag.Int("project", 0, "project of the created tasks")
+		billable     = flag.Bool("billable", false, "mark the created tasks billable")
+		allowOverlap = flag.Bool("allow-overlap", false, "create entries overlapping other entries of the user")
+	)
+	flag.Parse()
+
+	if *userId == 0 || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	storage, err := post.NewPG(context.Background(), cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		os.Exit(1)
+	}
+	defer storage.Close()
+
+	opts := icsimport.Options{
+		UserId:       *userId,
+		Category:     *category,
+		Keyword:      *keyword,
+		Billable:     *billable,
+		AllowOverlap: *allowOverlap,
+		Now:          time.Now(),
+	}
+	if *projectId != 0 {
+		opts.ProjectId = projectId
+	}
+
+	report, err := icsimport.Import(context.Background(), storage, in, opts)
+
+	printEntries("created", report.Created)
+	printEntries("skipped", report.Skipped)
+	printEntries("conflicting", report.Conflicting)
+
+	fmt.Printf("created %d, skipped %d, conflicting %d\n", len(report.Created), len(report.Skipped), len(report.Conflicting))
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to import calendar:", err)
+		os.Exit(1)
+	}
+}
+
+func printEntries(status string, entries []icsimport.Entry) {
+	for _, e := range entries {
+		line := status + "\t" + e.UID + "\t" + e.Summary
+		if e.Start != nil {
+			line += "\t" + e.Start.Format(time.RFC3339) + "\t" + e.End.Format(time.RFC3339)
+		}
+		if e.TaskId != 0 {
+			line += fmt.Sprintf("\ttask %d", e.TaskId)
+		}
+		if e.Reason != "" {
+			line += "\t" + e.Reason
+		}
+		if e.Error != "" {
+			line += ": " + e.Error
+		}
+
+		fmt.Println(line)
+	}
+}
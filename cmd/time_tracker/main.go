@@ -4,25 +4,58 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
 	"time"
+	_ "time/tzdata" // time zones of users do not depend on the system tz database
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 
 	"log/slog"
+	absCreate "time_tracker/internal/http-server/handlers/absence/create"
+	absGet "time_tracker/internal/http-server/handlers/absence/get"
+	absReview "time_tracker/internal/http-server/handlers/absence/review"
+	calFeed "time_tracker/internal/http-server/handlers/calendar/feed"
+	pCreate "time_tracker/internal/http-server/handlers/project/create"
+	pDelete "time_tracker/internal/http-server/handlers/project/delete"
+	pGet "time_tracker/internal/http-server/handlers/project/get"
+	pGetPT "time_tracker/internal/http-server/handlers/project/getProjectTime"
+	pUpdate "time_tracker/internal/http-server/handlers/project/update"
+	rGet "time_tracker/internal/http-server/handlers/rate/get"
+	rSet "time_tracker/internal/http-server/handlers/rate/set"
+	reportOvertime "time_tracker/internal/http-server/handlers/report/overtime"
+	reportTeam "time_tracker/internal/http-server/handlers/report/team"
+	tagAttach "time_tracker/internal/http-server/handlers/tag/attach"
+	tagDetach "time_tracker/internal/http-server/handlers/tag/detach"
+	tAudit "time_tracker/internal/http-server/handlers/task/audit"
+	tAutoStopped "time_tracker/internal/http-server/handlers/task/autoStopped"
 	tCreate "time_tracker/internal/http-server/handlers/task/create"
+	tDelete "time_tracker/internal/http-server/handlers/task/delete"
 	tGetUT "time_tracker/internal/http-server/handlers/task/getUserTasks"
+	tImport "time_tracker/internal/http-server/handlers/task/icsImport"
+	tList "time_tracker/internal/http-server/handlers/task/list"
+	tReview "time_tracker/internal/http-server/handlers/task/review"
+	tRunning "time_tracker/internal/http-server/handlers/task/running"
 	tStart "time_tracker/internal/http-server/handlers/task/start"
 	tStop "time_tracker/internal/http-server/handlers/task/stop"
+	tSummary "time_tracker/internal/http-server/handlers/task/summary"
+	tTimesheet "time_tracker/internal/http-server/handlers/task/timesheet"
+	tTree "time_tracker/internal/http-server/handlers/task/tree"
+	tUpdate "time_tracker/internal/http-server/handlers/task/update"
+	tsGet "time_tracker/internal/http-server/handlers/timesheet/get"
+	tsReview "time_tracker/internal/http-server/handlers/timesheet/review"
+	tsSubmit "time_tracker/internal/http-server/handlers/timesheet/submit"
 	uCreate "time_tracker/internal/http-server/handlers/user/create"
 
 	uDelete "time_tracker/internal/http-server/handlers/user/delete"
+	uFeedToken "time_tracker/internal/http-server/handlers/user/feedToken"
 	uGet "time_tracker/internal/http-server/handlers/user/get"
+	uSettings "time_tracker/internal/http-server/handlers/user/settings"
 	uUpdate "time_tracker/internal/http-server/handlers/user/update"
 
 	"time_tracker/internal/request/info"
@@ -32,8 +65,11 @@ import (
 
 	"time_tracker/internal/config"
 	mwLogger "time_tracker/internal/http-server/middleware/logger"
+	"time_tracker/internal/lib/export"
 	"time_tracker/internal/lib/logger/handlers/slogpretty"
 	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/reaper"
 	"time_tracker/internal/storage/post"
 
 	_ "time_tracker/docs" // Импортируйте ваши Swagger доки
@@ -72,7 +108,7 @@ func main() {
 	}
 	exPath := filepath.Dir(ex)
 	fmt.Println(exPath)
-	if err := runMigrations(cfg.StoragePath); err != nil {
+	if err := runMigrations(cfg.StoragePath, cfg.MigrationTimeZone); err != nil {
 		log.Error("failed to run migrations", sl.Err(err))
 		os.Exit(1)
 	}
@@ -85,6 +121,30 @@ func main() {
 	}
 	defer storage.Close()
 
+	csvDelimiter, err := export.Delimiter(cfg.Export.CSVDelimiter)
+	if err != nil {
+		log.Error("invalid csv delimiter", sl.Err(err))
+		os.Exit(1)
+	}
+
+	csvExport := export.CSV{Delimiter: csvDelimiter, Timeout: cfg.Export.Timeout}
+
+	pdfExport, err := export.NewPDF(cfg.Export.PDFFont)
+	if err != nil {
+		log.Error("failed to load pdf font", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if !pdfExport.Enabled() {
+		log.Warn("pdf font is not configured, pdf timesheets are disabled")
+	}
+
+	workCalendar, err := workcal.Load(cfg.Calendar.HolidaysPath, cfg.Calendar.WeekdayHours)
+	if err != nil {
+		log.Error("failed to load working calendar", sl.Err(err))
+		os.Exit(1)
+	}
+
 	infoS := info.NewRI()
 
 	router := chi.NewRouter()
@@ -92,8 +152,9 @@ func main() {
 		base/
 	*/
 	router.Use(middleware.RequestID)
-	router.Use(middleware.Logger)
-	router.Use(mwLogger.New(log))
+	// the token of a calendar feed is its only credential
+	router.Use(mwLogger.Skip(middleware.Logger, "/calendar/"))
+	router.Use(mwLogger.New(log, "/calendar/"))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
@@ -101,11 +162,49 @@ func main() {
 	router.Delete("/user", uDelete.New(context.Background(), log, storage))
 	router.Post("/user", uCreate.New(context.Background(), log, storage, infoS, cfg.Address))
 	router.Patch("/user", uUpdate.New(context.Background(), log, storage))
-
-	router.Post("/task", tCreate.New(context.Background(), log, storage))
-	router.Get("/task/task-time", tGetUT.New(context.Background(), log, storage))
-	router.Put("/task/start", tStart.New(context.Background(), log, storage))
-	router.Put("/task/stop", tStop.New(context.Background(), log, storage))
+	router.Put("/user/settings", uSettings.New(context.Background(), log, storage))
+	router.Put("/user/feed-token", uFeedToken.New(context.Background(), log, storage))
+
+	router.Post("/task", tCreate.New(context.Background(), log, storage, cfg.Tasks.EstimateThresholds))
+	router.Patch("/task", tUpdate.New(context.Background(), log, storage, cfg.Tasks.EstimateThresholds))
+	router.Delete("/task", tDelete.New(context.Background(), log, storage))
+	router.Get("/task/audit", tAudit.New(context.Background(), log, storage))
+	router.Get("/task/task-time", tGetUT.New(context.Background(), log, storage, cfg.Billing.Currency, csvExport, workCalendar))
+	router.Post("/task/import", tImport.New(context.Background(), log, storage))
+	router.Get("/tasks", tList.New(context.Background(), log, storage, csvExport))
+	router.Post("/task/tag", tagAttach.New(context.Background(), log, storage))
+	router.Delete("/task/tag", tagDetach.New(context.Background(), log, storage))
+	router.Get("/task/auto-stopped", tAutoStopped.New(context.Background(), log, storage))
+	router.Put("/task/review", tReview.New(context.Background(), log, storage))
+	router.Get("/task/summary", tSummary.New(context.Background(), log, storage, csvExport, workCalendar))
+	router.Get("/task/timesheet", tTimesheet.New(context.Background(), log, storage, pdfExport, workCalendar))
+	router.Get("/task/tree", tTree.New(context.Background(), log, storage))
+	router.Get("/task/running", tRunning.New(context.Background(), log, storage))
+	router.Put("/task/start", tStart.New(context.Background(), log, storage, cfg.Tasks.SingleActiveTimer))
+	router.Put("/task/stop", tStop.New(context.Background(), log, storage, cfg.Tasks.EstimateThresholds))
+
+	router.Post("/project", pCreate.New(context.Background(), log, storage))
+	router.Get("/project", pGet.New(context.Background(), log, storage))
+	router.Patch("/project", pUpdate.New(context.Background(), log, storage))
+	router.Delete("/project", pDelete.New(context.Background(), log, storage))
+	router.Get("/project/time", pGetPT.New(context.Background(), log, storage, csvExport))
+
+	router.Post("/rate", rSet.New(context.Background(), log, storage))
+	router.Get("/rate", rGet.New(context.Background(), log, storage, cfg.Billing.Currency))
+
+	router.Post("/timesheet", tsSubmit.New(context.Background(), log, storage))
+	router.Get("/timesheet", tsGet.New(context.Background(), log, storage))
+	router.Put("/timesheet/review", tsReview.New(context.Background(), log, storage))
+
+	router.Post("/absence", absCreate.New(context.Background(), log, storage))
+	router.Get("/absence", absGet.New(context.Background(), log, storage))
+	router.Put("/absence/review", absReview.New(context.Background(), log, storage))
+
+	router.Get("/report/team", reportTeam.New(context.Background(), log, storage, csvExport, workCalendar))
+	router.Get("/report/overtime", reportOvertime.New(context.Background(), log, storage, workCalendar, csvExport))
+
+	// middleware.URLFormat strips .ics from the path
+	router.Get("/calendar/{token}", calFeed.New(context.Background(), log, storage))
 
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
 
@@ -130,9 +229,14 @@ func main() {
 
 	log.Info("server started")
 
+	reaperCtx, stopReaper := context.WithCancel(context.Background())
+	go reaper.New(log, storage, cfg.Reaper.Interval, cfg.Reaper.MaxDuration, cfg.Tasks.EstimateThresholds).Run(reaperCtx)
+
 	<-done
 	log.Info("stopping server")
 
+	stopReaper()
+
 	// TODO: move timeout to config
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -183,10 +287,21 @@ func setupPrettySlog() *slog.Logger {
 	return slog.New(handler)
 }
 
-func runMigrations(databaseURL string) error {
+// runMigrations applies the migrations in a session with the TimeZone set to timeZone,
+// the migrations converting local timestamps read it with current_setting.
+func runMigrations(databaseURL string, timeZone string) error {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid storage path: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("timezone", timeZone)
+	u.RawQuery = q.Encode()
+
 	m, err := migrate.New(
 		"file://db/migrations",
-		databaseURL,
+		u.String(),
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,11 @@
+ALTER TABLE tasks ADD COLUMN start_time TIMESTAMP, ADD COLUMN end_time TIMESTAMP;
+
+UPDATE tasks SET start_time = s.started_at, end_time = s.stopped_at
+FROM (
+    SELECT task_id, MIN(started_at) AS started_at, MAX(stopped_at) AS stopped_at
+    FROM task_sessions
+    GROUP BY task_id
+) s
+WHERE tasks.id = s.task_id;
+
+DROP TABLE task_sessions;
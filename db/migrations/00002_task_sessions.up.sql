@@ -0,0 +1,15 @@
+CREATE TABLE task_sessions (
+    id SERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    stopped_at TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+-- a task can have only one open session at a time
+CREATE UNIQUE INDEX task_sessions_open_idx ON task_sessions (task_id) WHERE stopped_at IS NULL;
+
+INSERT INTO task_sessions (task_id, started_at, stopped_at)
+SELECT id, start_time, end_time FROM tasks WHERE start_time IS NOT NULL;
+
+ALTER TABLE tasks DROP COLUMN start_time, DROP COLUMN end_time;
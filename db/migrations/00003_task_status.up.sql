@@ -0,0 +1,8 @@
+ALTER TABLE tasks ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'created'
+    CHECK (status IN ('created', 'running', 'paused', 'done'));
+
+UPDATE tasks SET status = 'paused'
+WHERE EXISTS (SELECT 1 FROM task_sessions WHERE task_id = tasks.id);
+
+UPDATE tasks SET status = 'running'
+WHERE EXISTS (SELECT 1 FROM task_sessions WHERE task_id = tasks.id AND stopped_at IS NULL);
@@ -0,0 +1,14 @@
+-- no foreign key on task_id: the trail must outlive deleted tasks
+CREATE TABLE task_audit (
+    id SERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    session_id INT,
+    changed_by INT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    field VARCHAR(32),
+    old_value TEXT,
+    new_value TEXT,
+    changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX task_audit_task_id_idx ON task_audit (task_id);
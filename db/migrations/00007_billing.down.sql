@@ -0,0 +1,2 @@
+DROP TABLE hourly_rates;
+ALTER TABLE tasks DROP COLUMN billable;
@@ -0,0 +1,14 @@
+ALTER TABLE tasks ADD COLUMN billable BOOLEAN NOT NULL DEFAULT false;
+
+-- a rate belongs to a user, a project or a user on a project
+CREATE TABLE hourly_rates (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users (id) ON DELETE CASCADE,
+    project_id INT REFERENCES projects (id) ON DELETE CASCADE,
+    rate NUMERIC(12, 2) NOT NULL CHECK (rate >= 0),
+    effective_from DATE NOT NULL,
+    CHECK (user_id IS NOT NULL OR project_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX hourly_rates_key_idx
+    ON hourly_rates (COALESCE(user_id, 0), COALESCE(project_id, 0), effective_from);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP COLUMN needs_review;
+ALTER TABLE task_sessions DROP COLUMN auto_stopped;
+ALTER TABLE users DROP COLUMN end_of_day;
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN end_of_day TIME;
+
+ALTER TABLE task_sessions ADD COLUMN auto_stopped BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE tasks ADD COLUMN needs_review BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX tasks_needs_review_idx ON tasks (user_id) WHERE needs_review;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks ADD COLUMN parent_id INT REFERENCES tasks (id) ON DELETE SET NULL;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_parent_id_check CHECK (parent_id <> id);
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN estimate_reached;
+ALTER TABLE tasks DROP COLUMN estimate_minutes;
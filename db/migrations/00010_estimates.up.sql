@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN estimate_minutes INT CHECK (estimate_minutes > 0);
+
+-- the highest percent of the estimate the tracked time has reached, 0 if none
+ALTER TABLE tasks ADD COLUMN estimate_reached INT NOT NULL DEFAULT 0;
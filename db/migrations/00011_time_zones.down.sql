@@ -0,0 +1,8 @@
+ALTER TABLE users DROP COLUMN time_zone;
+
+ALTER TABLE task_audit
+    ALTER COLUMN changed_at TYPE TIMESTAMP USING changed_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE task_sessions
+    ALTER COLUMN started_at TYPE TIMESTAMP USING started_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN stopped_at TYPE TIMESTAMP USING stopped_at AT TIME ZONE current_setting('TimeZone');
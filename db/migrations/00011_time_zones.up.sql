@@ -0,0 +1,11 @@
+-- existing timestamps were written in the local time of the server, the migration runs
+-- with the TimeZone of the session set to that zone from migration_time_zone of the
+-- config so they keep their meaning
+ALTER TABLE task_sessions
+    ALTER COLUMN started_at TYPE TIMESTAMPTZ USING started_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN stopped_at TYPE TIMESTAMPTZ USING stopped_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE task_audit
+    ALTER COLUMN changed_at TYPE TIMESTAMPTZ USING changed_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE users ADD COLUMN time_zone TEXT NOT NULL DEFAULT 'UTC';
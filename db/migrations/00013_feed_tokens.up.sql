@@ -0,0 +1,2 @@
+-- sha256 of the secret token in the URL of the iCalendar feed of the user
+ALTER TABLE users ADD COLUMN feed_token_hash TEXT UNIQUE;
@@ -0,0 +1,4 @@
+-- UID of the calendar event a task was imported from, re-imports of the event are skipped
+ALTER TABLE tasks ADD COLUMN external_uid TEXT;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_user_id_external_uid_key UNIQUE (user_id, external_uid);
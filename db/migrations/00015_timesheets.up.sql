@@ -0,0 +1,18 @@
+-- a week or a month of a user submitted for approval, an approved period is locked
+CREATE TABLE timesheets (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    period TEXT NOT NULL CHECK (period IN ('week', 'month')),
+    -- days in the time zone of the user, period_end is the first day after the period
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('submitted', 'approved', 'rejected')),
+    submitted_at TIMESTAMPTZ NOT NULL,
+    reviewed_by INT REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    comment TEXT,
+    CHECK (period_end > period_start),
+    UNIQUE (user_id, period_start, period_end)
+);
+
+CREATE INDEX timesheets_status_idx ON timesheets (status, user_id);
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN part_time_factor;
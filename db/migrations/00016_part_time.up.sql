@@ -0,0 +1,3 @@
+-- share of the standard working hours the user is expected to work
+ALTER TABLE users ADD COLUMN part_time_factor NUMERIC(4, 3) NOT NULL DEFAULT 1
+    CHECK (part_time_factor > 0 AND part_time_factor <= 1);
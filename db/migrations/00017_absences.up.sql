@@ -0,0 +1,22 @@
+-- vacation, sick leave and other days off of a user, approved ones are credited in reports
+CREATE TABLE absences (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('vacation', 'sick', 'personal', 'other')),
+    -- days in the time zone of the user, end_date is the first day after the absence
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    -- a half-day absence is a single day
+    half_day BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL CHECK (status IN ('requested', 'approved', 'rejected')),
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    reviewed_by INT REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    review_comment TEXT,
+    CHECK (end_date > start_date),
+    CHECK (NOT half_day OR end_date = start_date + 1)
+);
+
+CREATE INDEX absences_user_idx ON absences (user_id, start_date);
+CREATE INDEX absences_status_idx ON absences (status, user_id);
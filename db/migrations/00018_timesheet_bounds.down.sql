@@ -0,0 +1,3 @@
+ALTER TABLE timesheets
+    DROP COLUMN locked_from,
+    DROP COLUMN locked_to;
@@ -0,0 +1,15 @@
+-- the instants a timesheet locks are fixed when it is submitted, so a later change
+-- of the time zone of the user does not move an approved period
+ALTER TABLE timesheets
+    ADD COLUMN locked_from TIMESTAMPTZ,
+    ADD COLUMN locked_to TIMESTAMPTZ;
+
+UPDATE timesheets SET
+    locked_from = timesheets.period_start::timestamp AT TIME ZONE users.time_zone,
+    locked_to = timesheets.period_end::timestamp AT TIME ZONE users.time_zone
+FROM users
+WHERE users.id = timesheets.user_id;
+
+ALTER TABLE timesheets
+    ALTER COLUMN locked_from SET NOT NULL,
+    ALTER COLUMN locked_to SET NOT NULL;
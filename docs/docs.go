@@ -17,15 +17,31 @@ const docTemplate = `{
     "paths": {
         "//task/task-time": {
             "get": {
-                "description": "получить userTaskTime по user_id и startPerio, endPeriod",
+                "description": "получить userTaskTime по user_id и startPerio, endPeriod или дням from, to в часовом поясе user, сессии обрезаются по границам периода, include_running учитывает запущенные task до текущего момента, время task суммируется по всем сессиям, фильтры project_ids и tags, group_by=project или tag, billing - billable время и сумма по ставкам; в xlsx без фильтров утвержденные отсутствия засчитываются в столбец Credited по рабочему календарю и part_time_factor user",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "summary": "Получить userTaskTime",
                 "operationId": "get-user_task_time-by-user_id-startPeriod-endPeriod",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json, csv или xlsx - табель по дням и task, по умолчанию по заголовку Accept; csv с group_by - итоги по project или tag",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "ok",
@@ -51,17 +67,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/task": {
+        "/absence": {
+            "get": {
+                "description": "получить отсутствия user_id или всех user, со status - только requested, approved или rejected, например ожидающие утверждения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить отсутствия",
+                "operationId": "get-absences",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handlers_absence_get.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "создать task по user_id и description",
+                "description": "запросить отсутствие user_id типа vacation, sick, personal или other за дни from, to в часовом поясе user, half_day - половина одного дня; после утверждения отсутствие засчитывается в сводке и переработках, не должно пересекаться с другими не отклоненными отсутствиями и утвержденными табелями",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Создать task",
-                "operationId": "create-task-by-user_id-description",
+                "summary": "Запросить отсутствие",
+                "operationId": "post-absence",
                 "responses": {
                     "200": {
                         "description": "ok",
@@ -76,25 +123,31 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "not save task",
+                        "description": "user not found",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "absence overlaps another absence or an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/task/start": {
+        "/absence/review": {
             "put": {
-                "description": "начать отчет времени task, поле start_time",
+                "description": "утвердить (approve=true) или отклонить с comment запрошенное отсутствие от имени reviewed_by; утвержденное отсутствие засчитывается в сводке и переработках, его нельзя утвердить в периоде утвержденного табеля",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "text/plain"
                 ],
-                "summary": "Начать task time",
-                "operationId": "put-task-of-start_time",
+                "summary": "Утвердить или отклонить отсутствие",
+                "operationId": "put-absence-review",
                 "responses": {
                     "200": {
                         "description": "ok"
@@ -106,37 +159,70 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "have't task",
+                        "description": "absence not found",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "absence is not requested, is reviewed by its user or is in an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/task/stop": {
-            "put": {
-                "description": "остановить отчет времени task, поле stop_time",
-                "consumes": [
-                    "application/json"
-                ],
+        "/calendar/{token}.ics": {
+            "get": {
+                "description": "получить iCalendar (.ics) с событием на каждую сессию task user, которому принадлежит token, для подписки в календаре; from и to - дни YYYY-MM-DD в часовом поясе user, по умолчанию последние 90 дней",
                 "produces": [
-                    "text/plain"
+                    "text/calendar"
+                ],
+                "summary": "Получить календарь времени user",
+                "operationId": "get-calendar-feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token календаря из PUT /user/feed-token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "первый день YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "последний день YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Остановить task time",
-                "operationId": "put-task-of-stop_time",
                 "responses": {
                     "200": {
-                        "description": "ok"
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "400": {
-                        "description": "empty body",
+                        "description": "invalid period",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "have't task",
+                        "description": "feed not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
                         "schema": {
                             "type": "string"
                         }
@@ -144,35 +230,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/project": {
             "get": {
-                "description": "получить user,также фильтрация и пагинация",
+                "description": "получить project по id или все project",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Получить user",
-                "operationId": "get-user-by-id",
+                "summary": "Получить project",
+                "operationId": "get-project-by-id",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/post.User"
-                            }
+                            "$ref": "#/definitions/internal_http-server_handlers_project_get.Response"
                         }
                     },
                     "400": {
-                        "description": "request body is empty",
+                        "description": "empty body",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "500": {
+                        "description": "error to DB",
                         "schema": {
                             "type": "string"
                         }
@@ -180,46 +263,46 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "создать user по паспорту и получить данные через другой сервис",
+                "description": "создать project по name и description",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Создать user",
-                "operationId": "create-user-by-passport",
+                "summary": "Создать project",
+                "operationId": "create-project",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/internal_http-server_handlers_project_create.Response"
                         }
                     },
                     "400": {
-                        "description": "not correct passport",
+                        "description": "empty body",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "not save user",
+                    "409": {
+                        "description": "project already exists",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "удалить user по id",
+                "description": "удалить project по id, task project остаются без project",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "text/plain"
                 ],
-                "summary": "Удалить user",
-                "operationId": "delete-user-by-id",
+                "summary": "Удалить project",
+                "operationId": "delete-project-by-id",
                 "responses": {
                     "200": {
                         "description": "ok"
@@ -231,7 +314,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "have't user",
+                        "description": "have't project",
                         "schema": {
                             "type": "string"
                         }
@@ -239,73 +322,2120 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "patch user by request date",
+                "description": "изменить name и description project",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "text/plain"
                 ],
-                "summary": "Изменить user",
-                "operationId": "patch-user-by-user-field",
+                "summary": "Изменить project",
+                "operationId": "patch-project-by-id",
                 "responses": {
                     "200": {
                         "description": "ok"
                     },
                     "400": {
-                        "description": "empty body!!",
+                        "description": "empty body",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Can not find ID",
+                        "description": "have't project",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "project already exists",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "post.TaskTime": {
-            "type": "object",
-            "properties": {
-                "hours": {
-                    "type": "number"
-                },
-                "minutes": {
-                    "type": "number"
-                },
-                "task_id": {
-                    "type": "integer"
+        },
+        "/project/time": {
+            "get": {
+                "description": "получить время всех user по project за startPeriod, endPeriod, сессии обрезаются по границам периода, include_running учитывает запущенные task, фильтры project_ids и user_ids",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "Получить время по project",
+                "operationId": "get-project-time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json или csv, по умолчанию по заголовку Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/getProjectTime.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "post.User": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "passportNumber": {
-                    "type": "integer"
-                },
-                "passportSerie": {
-                    "type": "integer"
-                },
-                "patronymic": {
-                    "type": "string"
-                },
-                "surname": {
-                    "type": "string"
+        "/rate": {
+            "get": {
+                "description": "получить часовые ставки по user_id и project_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить ставки",
+                "operationId": "get-rates",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handlers_rate_get.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "установить часовую ставку user, project или user в project с даты effective_from, ставка с той же датой заменяется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Установить ставку",
+                "operationId": "post-rate",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/set.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user or project not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/overtime": {
+            "get": {
+                "description": "получить ожидаемые часы по рабочему календарю с учетом праздников и part_time_factor user, отработанные часы, часы утвержденных отсутствий (credited_hours), переработки и недоработки user за дни from, to в часовом поясе user по дням и по неделям или месяцам (period=week, month), итог периода считается по его суммам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "Получить переработки user",
+                "operationId": "get-report-overtime",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json или csv - строка на каждый день и итог периода, по умолчанию по заголовку Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/overtime.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/team": {
+            "get": {
+                "description": "получить время user из user_ids и department за дни from, to в часовом поясе каждого user: итоги по user, по user и дням, по дням и общий итог; утвержденные отсутствия засчитываются в credited_seconds по рабочему календарю и part_time_factor каждого user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "summary": "Получить время команды",
+                "operationId": "get-team-time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json, csv или xlsx - лист табеля на каждого user и сводный лист, по умолчанию по заголовку Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/team.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task": {
+            "post": {
+                "description": "создать task по user_id, description, project_id, parent_id - task, подзадачей которого он будет, и estimate_minutes - оценка в минутах, с start_time и end_time создается завершенная запись времени, при превышении порогов оценки task отмечается",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать task",
+                "operationId": "create-task-by-user_id-description",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "int"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not save task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "time entry overlaps other entries or period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "удалить task по id вместе с сессиями, удаление пишется в audit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Удалить task",
+                "operationId": "delete-task-by-id",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task or changed_by user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "изменить description, billable, parent_id (0 - убрать из подзадач), estimate_minutes (0 - убрать оценку), start_time и end_time сессии task, изменения пишутся в audit, отметка превышения порогов оценки пересчитывается",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Изменить task",
+                "operationId": "patch-task-by-id",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task or changed_by user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "time entry overlaps other entries, parent_id makes a cycle or period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/audit": {
+            "get": {
+                "description": "получить историю изменений и удаления task: что изменено и кем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить audit task",
+                "operationId": "get-task-audit-by-task_id",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/audit.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/auto-stopped": {
+            "get": {
+                "description": "получить сессии task, остановленные автоматически и ожидающие проверки, по user_id или всех user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить автоматически остановленные task",
+                "operationId": "get-auto-stopped-tasks",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/autoStopped.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/import": {
+            "post": {
+                "description": "создать завершенные task user_id из событий файла .ics: category и keyword оставляют события с категорией или словом в summary и description, project_id и billable задаются всем task; события, импортированные ранее (по UID), пропускаются, пересекающиеся с другими записями времени возвращаются в conflicting, если не задан allow_overlap",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Импортировать task из календаря",
+                "operationId": "post-task-import",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "файл .ics",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id user",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "категория событий",
+                        "name": "category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "слово в summary или description событий",
+                        "name": "keyword",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "project task",
+                        "name": "project_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "task оплачиваемые",
+                        "name": "billable",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "разрешить пересечения с другими записями времени",
+                        "name": "allow_overlap",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/icsimport.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid calendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/review": {
+            "put": {
+                "description": "снять отметку проверки с автоматически остановленного task, изменение пишется в audit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Проверить task",
+                "operationId": "put-task-review",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task or changed_by user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/running": {
+            "get": {
+                "description": "получить запущенные task user с description, start_time текущей сессии и прошедшим временем, посчитанным на сервере",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить запущенные task",
+                "operationId": "get-running-tasks-by-user_id",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/running.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/start": {
+            "put": {
+                "description": "начать отчет времени task, открывает новую сессию task, task должен быть в статусе created или paused, при single_active_timer останавливает другие task user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Начать task time",
+                "operationId": "put-task-of-start_time",
+                "responses": {
+                    "200": {
+                        "description": "ok, auto_stopped - task остановленные автоматически",
+                        "schema": {
+                            "$ref": "#/definitions/start.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "illegal status transition or period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/stop": {
+            "put": {
+                "description": "остановить отчет времени task, закрывает открытую сессию task и ставит task на паузу, done завершает task, при превышении порогов оценки task отмечается",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Остановить task time",
+                "operationId": "put-task-of-stop_time",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "illegal status transition or period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/summary": {
+            "get": {
+                "description": "получить время user за дни from, to по дням, ISO неделям или месяцам (period=day, week, month) в часовом поясе user: итог каждого периода и время по description и project_id, фильтры project_ids и tags, include_running учитывает запущенные task; без фильтров утвержденные отсутствия засчитываются в credited_seconds по рабочему календарю и part_time_factor user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "summary": "Получить сводку времени user",
+                "operationId": "get-task-summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json, csv или xlsx - табель по периодам и task, по умолчанию по заголовку Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/summary.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/tag": {
+            "post": {
+                "description": "добавить tags к task, новые tags создаются, имя tag приводится к нижнему регистру",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Добавить tags к task",
+                "operationId": "post-task-tags",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "убрать tags у task, имя tag приводится к нижнему регистру",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Убрать tags у task",
+                "operationId": "delete-task-tags",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "period is locked by an approved timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/timesheet": {
+            "get": {
+                "description": "получить табель user за месяц month (YYYY-MM) в часовом поясе user для подписи: фамилия, имя и отчество user, время по дням и task, утвержденные отсутствия по рабочему календарю и part_time_factor user, итоги дней и месяца, строки для подписей, include_running учитывает запущенные task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "summary": "Получить табель user за месяц в PDF",
+                "operationId": "get-task-timesheet",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "pdf font is not configured",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/tree": {
+            "get": {
+                "description": "получить task со всеми подзадачами: время каждого task и общее время вместе с подзадачами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить дерево task",
+                "operationId": "get-task-tree-by-id",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/tree.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "description": "получить task с фильтрацией по user, status, периоду и подстроке description, сортировкой по start_time или duration и keyset пагинацией через cursor, в CSV выгружаются все task после cursor без limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "Получить список task",
+                "operationId": "get-tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json или csv, по умолчанию по заголовку Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "разделитель CSV, один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/list.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheet": {
+            "get": {
+                "description": "получить табели user_id или всех user, со status - только submitted, approved или rejected, например ожидающие утверждения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить табели",
+                "operationId": "get-timesheets",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handlers_timesheet_get.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "error to DB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "отправить на утверждение табель user_id за неделю или месяц (period=week, month), в который входит день date в часовом поясе user; отклоненный табель отправляется снова, у user не должно быть запущенных task в периоде; границы периода (locked_from, locked_to) фиксируются в часовом поясе user при отправке",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Отправить табель на утверждение",
+                "operationId": "post-timesheet",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "int"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "timesheet is already submitted or approved, overlaps another timesheet or a timer is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheet/review": {
+            "put": {
+                "description": "утвердить (approve=true) или отклонить с comment отправленный табель от имени reviewed_by; после утверждения task в периоде табеля нельзя запускать, останавливать, создавать, изменять и удалять",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Утвердить или отклонить табель",
+                "operationId": "put-timesheet-review",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "timesheet not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "timesheet is not submitted, is reviewed by its user or a timer is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "description": "получить user,также фильтрация и пагинация",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить user",
+                "operationId": "get-user-by-id",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/post.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "request body is empty",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "создать user по паспорту и получить данные через другой сервис",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать user",
+                "operationId": "create-user-by-passport",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "int"
+                        }
+                    },
+                    "400": {
+                        "description": "not correct passport",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not save user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "удалить user по id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Удалить user",
+                "operationId": "delete-user-by-id",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "patch user by request date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Изменить user",
+                "operationId": "patch-user-by-user-field",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body!!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Can not find ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/feed-token": {
+            "put": {
+                "description": "создать новый token календаря user по id, прежний token перестает работать; token показывается один раз, хранится только его хэш",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать token календаря user",
+                "operationId": "put-user-feed-token",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/feedToken.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/settings": {
+            "put": {
+                "description": "изменить настройки user, переданные в запросе: end_of_day - время остановки запущенных task, time_zone - часовой пояс IANA, в котором считаются дни отчетов, department - отдел user для отчетов по команде, part_time_factor - доля стандартных рабочих часов user от 0 до 1 для отчета о переработках",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Изменить настройки user",
+                "operationId": "put-user-settings",
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "empty body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "have't user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "audit.Response": {
+            "type": "object",
+            "properties": {
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.AuditRecord"
+                    }
+                }
+            }
+        },
+        "autoStopped.Response": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.AutoStopped"
+                    }
+                }
+            }
+        },
+        "feedToken.Response": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "description": "Path is the path of the calendar feed on this server.",
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "getProjectTime.Response": {
+            "type": "object",
+            "properties": {
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.ProjectTime"
+                    }
+                }
+            }
+        },
+        "icsimport.Entry": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error explains an invalid event.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "TaskId is the task created from the event.",
+                    "type": "integer"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "icsimport.Report": {
+            "type": "object",
+            "properties": {
+                "conflicting": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/icsimport.Entry"
+                    }
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/icsimport.Entry"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/icsimport.Entry"
+                    }
+                }
+            }
+        },
+        "internal_http-server_handlers_absence_get.Response": {
+            "type": "object",
+            "properties": {
+                "absences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.Absence"
+                    }
+                }
+            }
+        },
+        "internal_http-server_handlers_project_create.Response": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_http-server_handlers_project_get.Response": {
+            "type": "object",
+            "properties": {
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.Project"
+                    }
+                }
+            }
+        },
+        "internal_http-server_handlers_rate_get.Response": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.Rate"
+                    }
+                }
+            }
+        },
+        "internal_http-server_handlers_timesheet_get.Response": {
+            "type": "object",
+            "properties": {
+                "timesheets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.Timesheet"
+                    }
+                }
+            }
+        },
+        "list.Response": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.TaskRow"
+                    }
+                }
+            }
+        },
+        "overtime.Response": {
+            "type": "object",
+            "properties": {
+                "part_time_factor": {
+                    "type": "number"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.OvertimePeriod"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/report.WorkTime"
+                }
+            }
+        },
+        "post.Absence": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "half_day": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "review_comment": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/post.AbsenceStatus"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.AbsenceStatus": {
+            "type": "string",
+            "enum": [
+                "requested",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "AbsenceRequested",
+                "AbsenceApproved",
+                "AbsenceRejected"
+            ]
+        },
+        "post.AuditRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.AutoStopped": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.Project": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "post.ProjectTime": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "seconds": {
+                    "type": "number"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.Rate": {
+            "type": "object",
+            "properties": {
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.RunningTask": {
+            "type": "object",
+            "properties": {
+                "actual_minutes": {
+                    "description": "ActualMinutes is the time tracked on the task in all periods, RemainingMinutes\nis negative when the estimate is exceeded. Both are set only with an estimate.",
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "elapsed_seconds": {
+                    "description": "ElapsedSeconds is the duration of the open session, TotalSeconds includes earlier sessions.",
+                    "type": "number"
+                },
+                "estimate_minutes": {
+                    "type": "integer"
+                },
+                "estimate_reached": {
+                    "description": "EstimateReached is the highest alert threshold in percent the task has crossed.",
+                    "type": "integer"
+                },
+                "remaining_minutes": {
+                    "type": "number"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "total_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "post.TaskRow": {
+            "type": "object",
+            "properties": {
+                "actual_minutes": {
+                    "description": "ActualMinutes is the time tracked on the task in all periods, RemainingMinutes\nis negative when the estimate is exceeded. Both are set only with an estimate.",
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "number"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "estimate_minutes": {
+                    "type": "integer"
+                },
+                "estimate_reached": {
+                    "description": "EstimateReached is the highest alert threshold in percent the task has crossed.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "remaining_minutes": {
+                    "type": "number"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/post.TaskStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.TaskStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "running",
+                "paused",
+                "done"
+            ],
+            "x-enum-varnames": [
+                "TaskCreated",
+                "TaskRunning",
+                "TaskPaused",
+                "TaskDone"
+            ]
+        },
+        "post.TaskTime": {
+            "type": "object",
+            "properties": {
+                "actual_minutes": {
+                    "description": "ActualMinutes is the time tracked on the task in all periods, RemainingMinutes\nis negative when the estimate is exceeded. Both are set only with an estimate.",
+                    "type": "number"
+                },
+                "amount": {
+                    "description": "Amount is earned by a billable task, rounded to cents. MissingRate is set\nwhen some of its sessions have no hourly rate and were not counted.",
+                    "type": "string"
+                },
+                "billable": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_minutes": {
+                    "type": "integer"
+                },
+                "estimate_reached": {
+                    "description": "EstimateReached is the highest alert threshold in percent the task has crossed.",
+                    "type": "integer"
+                },
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "missing_rate": {
+                    "type": "boolean"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "remaining_minutes": {
+                    "type": "number"
+                },
+                "seconds": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "total_hours": {
+                    "description": "TotalHours, TotalMinutes and TotalSeconds add up the time of the task and all its subtasks.",
+                    "type": "number"
+                },
+                "total_minutes": {
+                    "type": "number"
+                },
+                "total_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "post.Timesheet": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locked_from": {
+                    "type": "string"
+                },
+                "locked_to": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/post.TimesheetStatus"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "post.TimesheetStatus": {
+            "type": "string",
+            "enum": [
+                "submitted",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "TimesheetSubmitted",
+                "TimesheetApproved",
+                "TimesheetRejected"
+            ]
+        },
+        "post.User": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passportNumber": {
+                    "type": "integer"
+                },
+                "passportSerie": {
+                    "type": "integer"
+                },
+                "patronymic": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "report.Bucket": {
+            "type": "object",
+            "properties": {
+                "credited_seconds": {
+                    "description": "CreditedSeconds is the time credited for approved absences, not part of Seconds.",
+                    "type": "number"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "hours": {
+                    "description": "Hours, Minutes and Seconds are the total of the bucket.",
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.SummaryItem"
+                    }
+                },
+                "key": {
+                    "description": "Key is the day (2024-03-05), the ISO week (2024-W10) or the month (2024-03).",
+                    "type": "string"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "seconds": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "report.DayTime": {
+            "type": "object",
+            "properties": {
+                "credited_seconds": {
+                    "description": "CreditedSeconds is the time credited for approved absences, not part of Seconds.",
+                    "type": "number"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.Node": {
+            "type": "object",
+            "properties": {
+                "actual_minutes": {
+                    "description": "ActualMinutes is the time tracked on the task in all periods, RemainingMinutes\nis negative when the estimate is exceeded. Both are set only with an estimate.",
+                    "type": "number"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.Node"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_minutes": {
+                    "type": "integer"
+                },
+                "estimate_reached": {
+                    "description": "EstimateReached is the highest alert threshold in percent the task has crossed.",
+                    "type": "integer"
+                },
+                "hours": {
+                    "description": "Hours and Minutes are the time of the task itself, the totals include its subtasks.",
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "remaining_minutes": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/post.TaskStatus"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "total_hours": {
+                    "type": "number"
+                },
+                "total_minutes": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.OvertimeDay": {
+            "type": "object",
+            "properties": {
+                "actual_hours": {
+                    "type": "number"
+                },
+                "credited_hours": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "expected_hours": {
+                    "type": "number"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "undertime_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.OvertimePeriod": {
+            "type": "object",
+            "properties": {
+                "actual_hours": {
+                    "type": "number"
+                },
+                "credited_hours": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.OvertimeDay"
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "expected_hours": {
+                    "type": "number"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "undertime_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.SummaryItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.Team": {
+            "type": "object",
+            "properties": {
+                "credited_seconds": {
+                    "description": "CreditedSeconds is the time credited for approved absences, not part of Seconds.",
+                    "type": "number"
+                },
+                "days": {
+                    "description": "Days are the totals of all users per day.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.DayTime"
+                    }
+                },
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "seconds": {
+                    "type": "number"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.TeamUser"
+                    }
+                }
+            }
+        },
+        "report.TeamUser": {
+            "type": "object",
+            "properties": {
+                "credited_seconds": {
+                    "description": "CreditedSeconds is the time credited for approved absences, not part of Seconds.",
+                    "type": "number"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.DayTime"
+                    }
+                },
+                "hours": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "seconds": {
+                    "type": "number"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "report.WorkTime": {
+            "type": "object",
+            "properties": {
+                "actual_hours": {
+                    "type": "number"
+                },
+                "credited_hours": {
+                    "type": "number"
+                },
+                "expected_hours": {
+                    "type": "number"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "undertime_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "response.Response": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "running.Response": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post.RunningTask"
+                    }
+                }
+            }
+        },
+        "set.Response": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "start.Response": {
+            "type": "object",
+            "properties": {
+                "auto_stopped": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "summary.Response": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.Bucket"
+                    }
+                },
+                "total_credited_seconds": {
+                    "description": "TotalCreditedSeconds is the time credited for approved absences, not part of TotalSeconds.",
+                    "type": "number"
+                },
+                "total_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "team.Response": {
+            "type": "object",
+            "properties": {
+                "team": {
+                    "$ref": "#/definitions/report.Team"
+                }
+            }
+        },
+        "tree.Response": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/report.Node"
                 }
             }
         }
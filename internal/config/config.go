@@ -12,6 +12,15 @@ type Config struct {
 	Env         string `yaml:"env" env-default:"local"`
 	StoragePath string `yaml:"storage_path" env-required:"true"`
 	HTTPServer  `yaml:"http_server"`
+	Tasks       `yaml:"tasks"`
+	Billing     `yaml:"billing"`
+	Reaper      `yaml:"reaper"`
+	Export      `yaml:"export"`
+	Calendar    `yaml:"calendar"`
+
+	// MigrationTimeZone is the zone of the timestamps written before they were
+	// stored with a time zone, the local zone of the server back then.
+	MigrationTimeZone string `yaml:"migration_time_zone" env-default:"UTC"`
 }
 
 type HTTPServer struct {
@@ -20,6 +29,42 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+type Tasks struct {
+	// SingleActiveTimer stops other running tasks of the user when a task is started.
+	SingleActiveTimer bool `yaml:"single_active_timer" env-default:"false"`
+	// EstimateThresholds are percents of the estimate at which an alert is raised.
+	EstimateThresholds []int `yaml:"estimate_thresholds" env-default:"80,100"`
+}
+
+type Billing struct {
+	// Currency of hourly rates and earnings in reports.
+	Currency string `yaml:"currency" env-default:"RUB"`
+}
+
+type Reaper struct {
+	Interval time.Duration `yaml:"interval" env-default:"5m"`
+	// MaxDuration is the longest a timer may run before it is stopped.
+	MaxDuration time.Duration `yaml:"max_duration" env-default:"12h"`
+}
+
+type Export struct {
+	// CSVDelimiter is a single character or "tab".
+	CSVDelimiter string `yaml:"csv_delimiter" env-default:","`
+	// Timeout is the write timeout of CSV exports instead of the server timeout.
+	Timeout time.Duration `yaml:"timeout" env-default:"5m"`
+	// PDFFont is a path to a TrueType font with Cyrillic letters of PDF timesheets,
+	// the timesheets are disabled if it is empty.
+	PDFFont string `yaml:"pdf_font"`
+}
+
+type Calendar struct {
+	// WeekdayHours are the standard working hours from Monday to Sunday.
+	WeekdayHours []float64 `yaml:"weekday_hours" env-default:"8,8,8,8,8,0,0"`
+	// HolidaysPath is a file of public holidays and other days that differ from
+	// the standard week, see workcal.Load.
+	HolidaysPath string `yaml:"holidays_path"`
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
@@ -37,5 +82,14 @@ func MustLoad() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	if _, err := time.LoadLocation(cfg.MigrationTimeZone); err != nil || cfg.MigrationTimeZone == "Local" {
+		log.Fatalf("invalid migration_time_zone: %s", cfg.MigrationTimeZone)
+	}
+
+	// a ticker panics on an interval that is not positive
+	if cfg.Reaper.Interval <= 0 || cfg.Reaper.MaxDuration <= 0 {
+		log.Fatalf("reaper interval and max_duration must be positive: %s, %s", cfg.Reaper.Interval, cfg.Reaper.MaxDuration)
+	}
+
 	return &cfg
 }
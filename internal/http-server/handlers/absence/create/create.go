@@ -0,0 +1,173 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+// MaxDays limits the length of an absence.
+const MaxDays = 366
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+	// Type is vacation, sick, personal or other.
+	Type string `json:"type" validate:"required"`
+	// From and To are YYYY-MM-DD days in the time zone of the user, both included.
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+	// HalfDay is an absence of half of a single day.
+	HalfDay bool    `json:"half_day,omitempty"`
+	Comment *string `json:"comment,omitempty"`
+}
+
+type Response struct {
+	Id int `json:"id,omitempty"`
+}
+
+type AbsenceCreate interface {
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	CreateAbsence(ctx context.Context, absence post.NewAbsence, now time.Time) (int, error)
+}
+
+// @Summary Запросить отсутствие
+// @Description запросить отсутствие user_id типа vacation, sick, personal или other за дни from, to в часовом поясе user, half_day - половина одного дня; после утверждения отсутствие засчитывается в сводке и переработках, не должно пересекаться с другими не отклоненными отсутствиями и утвержденными табелями
+// @ID post-absence
+// @Accept  json
+// @Produce  json
+// @Success 200 {int} id "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user not found"
+// @Failure 409 {object} response.Response "absence overlaps another absence or an approved timesheet"
+// @Router /absence [post]
+func New(context context.Context, log *slog.Logger, absenceCreate AbsenceCreate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.absence.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		switch req.Type {
+		case post.AbsenceVacation, post.AbsenceSick, post.AbsencePersonal, post.AbsenceOther:
+		default:
+			log.Info("invalid type", slog.String("type", req.Type))
+			http.Error(w, "type must be vacation, sick, personal or other", http.StatusBadRequest)
+			return
+		}
+
+		if req.Comment != nil && strings.TrimSpace(*req.Comment) == "" {
+			req.Comment = nil
+		}
+
+		loc, err := absenceCreate.GetUserLocation(context, req.UserId)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user time zone", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		start, end, err := interval.Days(req.From, req.To, loc)
+		if err != nil {
+			log.Info("invalid period", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if end.Sub(start) > MaxDays*24*time.Hour {
+			log.Info("absence too long", slog.String("from", req.From), slog.String("to", req.To))
+			http.Error(w, "absence must not be longer than a year", http.StatusBadRequest)
+			return
+		}
+
+		if req.HalfDay && req.From != req.To {
+			log.Info("half-day absence of several days", slog.String("from", req.From), slog.String("to", req.To))
+			http.Error(w, "half-day absence must be a single day", http.StatusBadRequest)
+			return
+		}
+
+		id, err := absenceCreate.CreateAbsence(context, post.NewAbsence{
+			UserId:    req.UserId,
+			Type:      req.Type,
+			StartDate: start,
+			EndDate:   end,
+			HalfDay:   req.HalfDay,
+			Comment:   req.Comment,
+		}, time.Now())
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		for _, conflict := range []struct {
+			err    error
+			reason string
+		}{
+			{storage.ErrAbsenceOverlap, "absence_overlap"},
+			{storage.ErrPeriodLocked, "period_locked"},
+		} {
+			if errors.Is(err, conflict.err) {
+				log.Info("absence not created", slog.Int("user_id", req.UserId), sl.Err(err))
+				resp.Error(w, r, http.StatusConflict, err.Error(), conflict.reason)
+				return
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to create absence", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("absence created", slog.Int("id", id), slog.Int("user_id", req.UserId))
+
+		responseOK(w, r, id)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, id int) {
+	render.JSON(w, r, Response{
+		Id: id,
+	})
+}
@@ -0,0 +1,94 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId *int `json:"user_id"`
+	// Status is requested, approved or rejected.
+	Status *post.AbsenceStatus `json:"status,omitempty"`
+}
+
+type Response struct {
+	Absences []post.Absence `json:"absences,omitempty"`
+}
+
+type AbsencesGet interface {
+	GetAbsences(ctx context.Context, userId *int, status *post.AbsenceStatus) ([]post.Absence, error)
+}
+
+// @Summary Получить отсутствия
+// @Description получить отсутствия user_id или всех user, со status - только requested, approved или rejected, например ожидающие утверждения
+// @ID get-absences
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /absence [get]
+func New(context context.Context, log *slog.Logger, absencesGet AbsencesGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.absence.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Status != nil {
+			switch *req.Status {
+			case post.AbsenceRequested, post.AbsenceApproved, post.AbsenceRejected:
+			default:
+				log.Info("invalid status", slog.String("status", string(*req.Status)))
+				http.Error(w, "status must be requested, approved or rejected", http.StatusBadRequest)
+				return
+			}
+		}
+
+		absences, err := absencesGet.GetAbsences(context, req.UserId, req.Status)
+		if err != nil {
+			log.Error("failed to get absences", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("absences get", slog.Int("count", len(absences)))
+
+		responseOK(w, r, absences)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, absences []post.Absence) {
+	render.JSON(w, r, Response{
+		Absences: absences,
+	})
+}
@@ -0,0 +1,124 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id         int  `json:"id" validate:"required"`
+	ReviewedBy int  `json:"reviewed_by" validate:"required"`
+	Approve    bool `json:"approve"`
+	// Comment is required to reject an absence.
+	Comment *string `json:"comment,omitempty"`
+}
+
+type AbsenceReview interface {
+	ReviewAbsence(ctx context.Context, id int, reviewedBy int, approve bool, comment *string, now time.Time) error
+}
+
+// @Summary Утвердить или отклонить отсутствие
+// @Description утвердить (approve=true) или отклонить с comment запрошенное отсутствие от имени reviewed_by; утвержденное отсутствие засчитывается в сводке и переработках, его нельзя утвердить в периоде утвержденного табеля
+// @ID put-absence-review
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "absence not found"
+// @Failure 409 {object} response.Response "absence is not requested, is reviewed by its user or is in an approved timesheet"
+// @Router /absence/review [put]
+func New(context context.Context, log *slog.Logger, absenceReview AbsenceReview) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.absence.review.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.ReviewedBy == 0 {
+			log.Info("reviewed_by is empty")
+			http.Error(w, "reviewed_by is required", http.StatusBadRequest)
+			return
+		}
+
+		if req.Comment != nil && strings.TrimSpace(*req.Comment) == "" {
+			req.Comment = nil
+		}
+
+		if !req.Approve && req.Comment == nil {
+			log.Info("rejection without comment", slog.Int("id", req.Id))
+			http.Error(w, "comment is required to reject an absence", http.StatusBadRequest)
+			return
+		}
+
+		err = absenceReview.ReviewAbsence(context, req.Id, req.ReviewedBy, req.Approve, req.Comment, time.Now())
+
+		if errors.Is(err, storage.ErrAbsenceNotFound) {
+			log.Info("absence not found", slog.Int("id", req.Id))
+			http.Error(w, "absence not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("reviewer not found", slog.Int("reviewed_by", req.ReviewedBy))
+			http.Error(w, "reviewer not found", http.StatusNotFound)
+			return
+		}
+
+		for _, conflict := range []struct {
+			err    error
+			reason string
+		}{
+			{storage.ErrNotRequested, "absence_not_requested"},
+			{storage.ErrOwnAbsence, "own_absence"},
+			{storage.ErrPeriodLocked, "period_locked"},
+		} {
+			if errors.Is(err, conflict.err) {
+				log.Info("absence not reviewed", slog.Int("id", req.Id), sl.Err(err))
+				resp.Error(w, r, http.StatusConflict, err.Error(), conflict.reason)
+				return
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to review absence", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("absence reviewed", slog.Int("id", req.Id), slog.Int("reviewed_by", req.ReviewedBy), slog.Bool("approve", req.Approve))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
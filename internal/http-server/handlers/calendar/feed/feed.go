@@ -0,0 +1,129 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"time_tracker/internal/lib/ical"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+const (
+	// MaxDays limits the window of the feed.
+	MaxDays = 366
+	// DefaultDays is the window of the feed up to today if from and to are not set.
+	DefaultDays = 90
+)
+
+type FeedGet interface {
+	GetFeedUser(ctx context.Context, token string) (int, *time.Location, error)
+	GetCalendarEntries(ctx context.Context, userId int, startPeriod, endPeriod, now time.Time) ([]post.CalendarEntry, error)
+}
+
+// @Summary Получить календарь времени user
+// @Description получить iCalendar (.ics) с событием на каждую сессию task user, которому принадлежит token, для подписки в календаре; from и to - дни YYYY-MM-DD в часовом поясе user, по умолчанию последние 90 дней
+// @ID get-calendar-feed
+// @Produce  text/calendar
+// @Param token path string true "token календаря из PUT /user/feed-token"
+// @Param from query string false "первый день YYYY-MM-DD"
+// @Param to query string false "последний день YYYY-MM-DD"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "invalid period"
+// @Failure 404 {string} string "feed not found"
+// @Failure 500 {string} string "error to DB"
+// @Router /calendar/{token}.ics [get]
+func New(context context.Context, log *slog.Logger, feedGet FeedGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.feed.New"
+
+		// the token is not logged, it is the only credential of the feed; the logging
+		// middleware redacts it from the path
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userId, loc, err := feedGet.GetFeedUser(context, chi.URLParam(r, "token"))
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("feed not found")
+			http.Error(w, "feed not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get feed user", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		now := time.Now()
+
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		if to == "" {
+			to = now.In(loc).Format(interval.DateLayout)
+		}
+		if from == "" {
+			last, err := time.ParseInLocation(interval.DateLayout, to, loc)
+			if err == nil {
+				from = last.AddDate(0, 0, 1-DefaultDays).Format(interval.DateLayout)
+			}
+		}
+
+		start, end, err := interval.Days(from, to, loc)
+		if err != nil {
+			log.Info("invalid period", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if end.Sub(start) > MaxDays*24*time.Hour {
+			log.Info("period too long", slog.String("from", from), slog.String("to", to))
+			http.Error(w, "period must not be longer than a year", http.StatusBadRequest)
+			return
+		}
+
+		entries, err := feedGet.GetCalendarEntries(context, userId, start, end, now)
+		if err != nil {
+			log.Error("failed to get calendar entries", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		events := make([]ical.Event, 0, len(entries))
+		for _, e := range entries {
+			var description string
+			if e.ProjectId != nil {
+				description = "project " + strconv.Itoa(*e.ProjectId)
+			}
+
+			events = append(events, ical.Event{
+				UID:         "task-" + strconv.Itoa(e.TaskId) + "-session-" + strconv.Itoa(e.SessionId) + "@time_tracker",
+				Start:       e.StartedAt,
+				End:         e.StoppedAt,
+				Summary:     e.Description,
+				Description: description,
+			})
+		}
+
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", `inline; filename="time.ics"`)
+
+		if err := ical.Write(w, "Time tracker", events, now); err != nil {
+			log.Error("failed to write calendar", sl.Err(err))
+			return
+		}
+
+		log.Info("calendar feed get", slog.Int("user_id", userId), slog.Int("events", len(events)))
+	}
+}
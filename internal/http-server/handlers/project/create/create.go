@@ -0,0 +1,97 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type Response struct {
+	Id int `json:"id,omitempty"`
+}
+
+type ProjectCreate interface {
+	CreateProject(ctx context.Context, name, description string) (int, error)
+}
+
+// @Summary Создать project
+// @Description создать project по name и description
+// @ID create-project
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 409 {object} response.Response "project already exists"
+// @Router /project [post]
+func New(context context.Context, log *slog.Logger, projectCreate ProjectCreate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.project.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Name == "" {
+			log.Info("project name is empty")
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		id, err := projectCreate.CreateProject(context, req.Name, req.Description)
+
+		if errors.Is(err, storage.ErrProjectExists) {
+			log.Info("project already exists", slog.String("name", req.Name))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "project_exists")
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to add project", sl.Err(err))
+			http.Error(w, "not save project", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("project added", slog.Int("id", id))
+
+		responseOK(w, r, id)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, id int) {
+	render.JSON(w, r, Response{
+		Id: id,
+	})
+}
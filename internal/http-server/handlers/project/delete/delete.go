@@ -0,0 +1,79 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id int `json:"id" validate:"required"`
+}
+
+type ProjectDelete interface {
+	DeleteProject(ctx context.Context, id int) error
+}
+
+// @Summary Удалить project
+// @Description удалить project по id, task project остаются без project
+// @ID delete-project-by-id
+// @Accept  json
+// @Produce text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't project"
+// @Router /project [delete]
+func New(context context.Context, log *slog.Logger, projectDelete ProjectDelete) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.project.delete.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		err = projectDelete.DeleteProject(context, req.Id)
+
+		if errors.Is(err, storage.ErrProjectNotFound) {
+			log.Info("project not found", slog.Int("id", req.Id))
+			http.Error(w, "have't project", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to delete project", sl.Err(err))
+			http.Error(w, "not delete project", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("project delete", slog.Int("id", req.Id))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,82 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	Id *int `json:"id"`
+}
+
+type Response struct {
+	Projects []post.Project `json:"projects,omitempty"`
+}
+
+type ProjectGet interface {
+	GetProjects(ctx context.Context, id *int) ([]post.Project, error)
+}
+
+// @Summary Получить project
+// @Description получить project по id или все project
+// @ID get-project-by-id
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /project [get]
+func New(context context.Context, log *slog.Logger, projectGet ProjectGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.project.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		projects, err := projectGet.GetProjects(context, req.Id)
+		if err != nil {
+			log.Error("failed to get projects", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("projects get", slog.Int("count", len(projects)))
+
+		responseOK(w, r, projects)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, projects []post.Project) {
+	render.JSON(w, r, Response{
+		Projects: projects,
+	})
+}
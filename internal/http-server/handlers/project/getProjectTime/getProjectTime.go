@@ -0,0 +1,120 @@
+package getProjectTime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	ProjectIds  []int     `json:"project_ids,omitempty"`
+	UserIds     []int     `json:"user_ids,omitempty"`
+	StartPeriod time.Time `json:"startPeriod"`
+	EndPeriod   time.Time `json:"endPeriod"`
+	// IncludeRunning counts running tasks up to now.
+	IncludeRunning bool `json:"include_running,omitempty"`
+}
+
+type Response struct {
+	Projects []post.ProjectTime `json:"projects,omitempty"`
+}
+
+type ProjectTimeGet interface {
+	GetProjectTime(ctx context.Context, filter post.ProjectTimeFilter) ([]post.ProjectTime, error)
+}
+
+// @Summary Получить время по project
+// @Description получить время всех user по project за startPeriod, endPeriod, сессии обрезаются по границам периода, include_running учитывает запущенные task, фильтры project_ids и user_ids
+// @ID get-project-time
+// @Accept  json
+// @Produce  json,text/csv
+// @Param format query string false "json или csv, по умолчанию по заголовку Accept"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /project/time [get]
+func New(context context.Context, log *slog.Logger, projectTimeGet ProjectTimeGet, csvExport export.CSV) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.project.getProjectTime.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		projects, err := projectTimeGet.GetProjectTime(context, post.ProjectTimeFilter{
+			ProjectIds:     req.ProjectIds,
+			UserIds:        req.UserIds,
+			StartPeriod:    req.StartPeriod,
+			EndPeriod:      req.EndPeriod,
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to get project time", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("project time get", slog.Int("count", len(projects)))
+
+		if respFormat == format.CSV {
+			if err := export.WriteProjectTimes(csvOut.Start(w, "project-time"), projects); err != nil {
+				log.Error("failed to write csv", sl.Err(err))
+			}
+			return
+		}
+
+		responseOK(w, r, projects)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, projects []post.ProjectTime) {
+	render.JSON(w, r, Response{
+		Projects: projects,
+	})
+}
@@ -0,0 +1,95 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id          int    `json:"id" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type ProjectUpdate interface {
+	UpdateProject(ctx context.Context, id int, name, description string) error
+}
+
+// @Summary Изменить project
+// @Description изменить name и description project
+// @ID patch-project-by-id
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't project"
+// @Failure 409 {object} response.Response "project already exists"
+// @Router /project [patch]
+func New(context context.Context, log *slog.Logger, projectUpdate ProjectUpdate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.project.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Name == "" {
+			log.Info("project name is empty")
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		err = projectUpdate.UpdateProject(context, req.Id, req.Name, req.Description)
+
+		if errors.Is(err, storage.ErrProjectExists) {
+			log.Info("project already exists", slog.String("name", req.Name))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "project_exists")
+			return
+		}
+
+		if errors.Is(err, storage.ErrProjectNotFound) {
+			log.Info("project not found", slog.Int("id", req.Id))
+			http.Error(w, "have't project", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to update project", sl.Err(err))
+			http.Error(w, "not update project", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("project update", slog.Int("id", req.Id))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
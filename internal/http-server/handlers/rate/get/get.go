@@ -0,0 +1,85 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId    *int `json:"user_id"`
+	ProjectId *int `json:"project_id"`
+}
+
+type Response struct {
+	Rates    []post.Rate `json:"rates,omitempty"`
+	Currency string      `json:"currency"`
+}
+
+type RateGet interface {
+	GetRates(ctx context.Context, userId, projectId *int) ([]post.Rate, error)
+}
+
+// @Summary Получить ставки
+// @Description получить часовые ставки по user_id и project_id
+// @ID get-rates
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /rate [get]
+func New(context context.Context, log *slog.Logger, rateGet RateGet, currency string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rate.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		rates, err := rateGet.GetRates(context, req.UserId, req.ProjectId)
+		if err != nil {
+			log.Error("failed to get rates", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("rates get", slog.Int("count", len(rates)))
+
+		responseOK(w, r, rates, currency)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, rates []post.Rate, currency string) {
+	render.JSON(w, r, Response{
+		Rates:    rates,
+		Currency: currency,
+	})
+}
@@ -0,0 +1,120 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/shopspring/decimal"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId    *int            `json:"user_id,omitempty"`
+	ProjectId *int            `json:"project_id,omitempty"`
+	Rate      decimal.Decimal `json:"rate" validate:"required" swaggertype:"string"`
+	// EffectiveFrom is a date in the YYYY-MM-DD format.
+	EffectiveFrom string `json:"effective_from" validate:"required"`
+}
+
+type Response struct {
+	Id int `json:"id,omitempty"`
+}
+
+type RateSet interface {
+	SetRate(ctx context.Context, rate post.Rate) (int, error)
+}
+
+// @Summary Установить ставку
+// @Description установить часовую ставку user, project или user в project с даты effective_from, ставка с той же датой заменяется
+// @ID post-rate
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user or project not found"
+// @Router /rate [post]
+func New(context context.Context, log *slog.Logger, rateSet RateSet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rate.set.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.UserId == nil && req.ProjectId == nil {
+			log.Info("rate without owner")
+			http.Error(w, "user_id or project_id is required", http.StatusBadRequest)
+			return
+		}
+
+		if req.Rate.IsNegative() {
+			log.Info("negative rate", slog.String("rate", req.Rate.String()))
+			http.Error(w, "rate must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		effectiveFrom, err := time.Parse(time.DateOnly, req.EffectiveFrom)
+		if err != nil {
+			log.Info("invalid effective_from", sl.Err(err))
+			http.Error(w, "effective_from must be a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+
+		id, err := rateSet.SetRate(context, post.Rate{
+			UserId:        req.UserId,
+			ProjectId:     req.ProjectId,
+			Rate:          req.Rate,
+			EffectiveFrom: effectiveFrom,
+		})
+
+		if errors.Is(err, storage.ErrUserNotFound) || errors.Is(err, storage.ErrProjectNotFound) {
+			log.Info("rate owner not found", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to set rate", sl.Err(err))
+			http.Error(w, "not save rate", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("rate set", slog.Int("id", id))
+
+		responseOK(w, r, id)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, id int) {
+	render.JSON(w, r, Response{
+		Id: id,
+	})
+}
@@ -0,0 +1,190 @@
+package overtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+// MaxDays limits the period of an overtime report.
+const MaxDays = 366
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+	// From and To are YYYY-MM-DD days in the time zone of the user, both included.
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+	// Period groups the days by week or month, month if omitted.
+	Period         string `json:"period,omitempty"`
+	IncludeRunning bool   `json:"include_running,omitempty"`
+}
+
+type Response struct {
+	PartTimeFactor float64                 `json:"part_time_factor"`
+	Periods        []report.OvertimePeriod `json:"periods,omitempty"`
+	Total          report.WorkTime         `json:"total"`
+}
+
+type OvertimeGet interface {
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	GetPartTimeFactor(ctx context.Context, id int) (float64, error)
+	GetUserSessions(ctx context.Context, filter post.ReportFilter) ([]post.SessionTime, error)
+	GetApprovedAbsences(ctx context.Context, userId int, start, end time.Time) ([]post.Absence, error)
+}
+
+// @Summary Получить переработки user
+// @Description получить ожидаемые часы по рабочему календарю с учетом праздников и part_time_factor user, отработанные часы, часы утвержденных отсутствий (credited_hours), переработки и недоработки user за дни from, to в часовом поясе user по дням и по неделям или месяцам (period=week, month), итог периода считается по его суммам
+// @ID get-report-overtime
+// @Accept  json
+// @Produce  json,text/csv
+// @Param format query string false "json или csv - строка на каждый день и итог периода, по умолчанию по заголовку Accept"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user not found"
+// @Failure 500 {string} string "error to DB"
+// @Router /report/overtime [get]
+func New(context context.Context, log *slog.Logger, overtimeGet OvertimeGet, cal *workcal.Calendar, csvExport export.CSV) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.report.overtime.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		switch req.Period {
+		case "":
+			req.Period = report.PeriodMonth
+		case report.PeriodWeek, report.PeriodMonth:
+		default:
+			log.Info("invalid period", slog.String("period", req.Period))
+			http.Error(w, "period must be week or month", http.StatusBadRequest)
+			return
+		}
+
+		loc, err := overtimeGet.GetUserLocation(context, req.UserId)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user time zone", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		start, end, err := interval.Days(req.From, req.To, loc)
+		if err != nil {
+			log.Info("invalid period", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if end.Sub(start) > MaxDays*24*time.Hour {
+			log.Info("period too long", slog.String("from", req.From), slog.String("to", req.To))
+			http.Error(w, "period must not be longer than a year", http.StatusBadRequest)
+			return
+		}
+
+		factor, err := overtimeGet.GetPartTimeFactor(context, req.UserId)
+		if err != nil {
+			log.Error("failed to get part-time factor", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		sessions, err := overtimeGet.GetUserSessions(context, post.ReportFilter{
+			UserId:         req.UserId,
+			StartPeriod:    start,
+			EndPeriod:      end,
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to get user sessions", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		absences, err := overtimeGet.GetApprovedAbsences(context, req.UserId, start, end)
+		if err != nil {
+			log.Error("failed to get user absences", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		days := report.Summary(sessions, report.PeriodDay, start, end)
+		report.Credit(days, report.AbsenceCredit(absences, cal, factor))
+		periods, total := report.Overtime(days, cal, factor, req.Period)
+
+		log.Info("overtime get", slog.Int("user_id", req.UserId), slog.Int("periods", len(periods)))
+
+		if respFormat == format.CSV {
+			if err := export.WriteOvertime(csvOut.Start(w, "overtime"), periods); err != nil {
+				log.Error("failed to write csv", sl.Err(err))
+			}
+			return
+		}
+
+		responseOK(w, r, factor, periods, total)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, factor float64, periods []report.OvertimePeriod, total report.WorkTime) {
+	render.JSON(w, r, Response{
+		PartTimeFactor: factor,
+		Periods:        periods,
+		Total:          total,
+	})
+}
@@ -0,0 +1,193 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage/post"
+)
+
+// MaxDays limits the period of a team report.
+const MaxDays = 366
+
+type Request struct {
+	UserIds    []int   `json:"user_ids,omitempty"`
+	Department *string `json:"department,omitempty"`
+	// From and To are YYYY-MM-DD days, both included, in the time zone of each user.
+	From           string `json:"from" validate:"required"`
+	To             string `json:"to" validate:"required"`
+	IncludeRunning bool   `json:"include_running,omitempty"`
+}
+
+type Response struct {
+	Team report.Team `json:"team"`
+}
+
+type TeamTimeGet interface {
+	GetTeamTime(ctx context.Context, filter post.TeamFilter) ([]post.TeamDay, error)
+	GetTeamSessions(ctx context.Context, filter post.TeamFilter) ([]post.TeamSession, error)
+	GetTeamAbsences(ctx context.Context, filter post.TeamFilter) ([]post.TeamAbsence, error)
+}
+
+// @Summary Получить время команды
+// @Description получить время user из user_ids и department за дни from, to в часовом поясе каждого user: итоги по user, по user и дням, по дням и общий итог; утвержденные отсутствия засчитываются в credited_seconds по рабочему календарю и part_time_factor каждого user
+// @ID get-team-time
+// @Accept  json
+// @Produce  json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "json, csv или xlsx - лист табеля на каждого user и сводный лист, по умолчанию по заголовку Accept"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /report/team [get]
+func New(context context.Context, log *slog.Logger, teamTimeGet TeamTimeGet, csvExport export.CSV, cal *workcal.Calendar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.report.team.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if len(req.UserIds) == 0 && req.Department == nil {
+			log.Info("no users selected")
+			http.Error(w, "user_ids or department is required", http.StatusBadRequest)
+			return
+		}
+
+		// the days are only checked here, each user counts them in own time zone
+		from, end, err := interval.Days(req.From, req.To, time.UTC)
+		if err != nil {
+			log.Info("invalid period", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if end.Sub(from) > MaxDays*24*time.Hour {
+			log.Info("period too long", slog.String("from", req.From), slog.String("to", req.To))
+			http.Error(w, "period must not be longer than a year", http.StatusBadRequest)
+			return
+		}
+
+		filter := post.TeamFilter{
+			UserIds:        req.UserIds,
+			Department:     req.Department,
+			From:           from,
+			To:             end.AddDate(0, 0, -1),
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		}
+
+		absences, err := teamTimeGet.GetTeamAbsences(context, filter)
+		if err != nil {
+			log.Error("failed to get team absences", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		credit := report.TeamCredit(absences, cal)
+
+		if respFormat == format.XLSX {
+			writeXLSX(context, log, w, teamTimeGet, filter, credit)
+			return
+		}
+
+		days, err := teamTimeGet.GetTeamTime(context, filter)
+		if err != nil {
+			log.Error("failed to get team time", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		team := report.TeamTime(days)
+		report.CreditTeam(&team, credit)
+
+		log.Info("team time get", slog.Int("users", len(team.Users)))
+
+		if respFormat == format.CSV {
+			if err := export.WriteTeam(csvOut.Start(w, "team"), team); err != nil {
+				log.Error("failed to write csv", sl.Err(err))
+			}
+			return
+		}
+
+		responseOK(w, r, team)
+	}
+}
+
+func writeXLSX(ctx context.Context, log *slog.Logger, w http.ResponseWriter, teamTimeGet TeamTimeGet, filter post.TeamFilter, credit map[int]map[string]float64) {
+	sessions, err := teamTimeGet.GetTeamSessions(ctx, filter)
+	if err != nil {
+		log.Error("failed to get team sessions", sl.Err(err))
+		http.Error(w, "error to DB", http.StatusInternalServerError)
+		return
+	}
+
+	sheets, err := report.TeamTimesheets(sessions, filter.From, filter.To)
+	if err == nil {
+		for _, sheet := range sheets {
+			report.Credit(sheet.Rows, credit[sheet.UserId])
+		}
+
+		err = export.WriteXLSX(w, "team", sheets)
+	}
+
+	if err != nil {
+		log.Error("failed to write xlsx", sl.Err(err))
+		http.Error(w, "failed to write xlsx", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("team timesheets get", slog.Int("users", len(sheets)))
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, team report.Team) {
+	render.JSON(w, r, Response{
+		Team: team,
+	})
+}
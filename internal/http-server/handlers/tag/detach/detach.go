@@ -0,0 +1,105 @@
+package detach
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	TaskId int      `json:"task_id" validate:"required"`
+	Tags   []string `json:"tags" validate:"required"`
+}
+
+type TagsDetach interface {
+	DetachTags(ctx context.Context, taskId int, names []string) error
+}
+
+// @Summary Убрать tags у task
+// @Description убрать tags у task, имя tag приводится к нижнему регистру
+// @ID delete-task-tags
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't task"
+// @Failure 409 {object} response.Response "period is locked by an approved timesheet"
+// @Router /task/tag [delete]
+func New(context context.Context, log *slog.Logger, tagsDetach TagsDetach) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tag.detach.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if len(req.Tags) == 0 {
+			log.Info("tags are empty")
+			http.Error(w, "tags are required", http.StatusBadRequest)
+			return
+		}
+
+		for _, tag := range req.Tags {
+			if utf8.RuneCountInString(strings.TrimSpace(tag)) > post.MaxTagLength {
+				log.Info("tag too long", slog.String("tag", tag))
+				http.Error(w, "tag must not be longer than 50 characters", http.StatusBadRequest)
+				return
+			}
+		}
+
+		err = tagsDetach.DetachTags(context, req.TaskId, req.Tags)
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("task_id", req.TaskId))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int("task_id", req.TaskId))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to detach tags", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("tags detach", slog.Int("task_id", req.TaskId), slog.Any("tags", req.Tags))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	TaskId int `json:"task_id" validate:"required"`
+}
+
+type Response struct {
+	Records []post.AuditRecord `json:"records,omitempty"`
+}
+
+type TaskAuditGet interface {
+	GetTaskAudit(ctx context.Context, taskId int) ([]post.AuditRecord, error)
+}
+
+// @Summary Получить audit task
+// @Description получить историю изменений и удаления task: что изменено и кем
+// @ID get-task-audit-by-task_id
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /task/audit [get]
+func New(context context.Context, log *slog.Logger, taskAuditGet TaskAuditGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.audit.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		records, err := taskAuditGet.GetTaskAudit(context, req.TaskId)
+		if err != nil {
+			log.Error("failed to get task audit", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("task audit get", slog.Int("task_id", req.TaskId))
+
+		responseOK(w, r, records)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, records []post.AuditRecord) {
+	render.JSON(w, r, Response{
+		Records: records,
+	})
+}
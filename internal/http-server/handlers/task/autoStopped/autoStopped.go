@@ -0,0 +1,82 @@
+package autoStopped
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId *int `json:"user_id"`
+}
+
+type Response struct {
+	Sessions []post.AutoStopped `json:"sessions,omitempty"`
+}
+
+type AutoStoppedGet interface {
+	GetAutoStopped(ctx context.Context, userId *int) ([]post.AutoStopped, error)
+}
+
+// @Summary Получить автоматически остановленные task
+// @Description получить сессии task, остановленные автоматически и ожидающие проверки, по user_id или всех user
+// @ID get-auto-stopped-tasks
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /task/auto-stopped [get]
+func New(context context.Context, log *slog.Logger, autoStoppedGet AutoStoppedGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.autoStopped.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		sessions, err := autoStoppedGet.GetAutoStopped(context, req.UserId)
+		if err != nil {
+			log.Error("failed to get auto-stopped tasks", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("auto-stopped tasks get", slog.Int("count", len(sessions)))
+
+		responseOK(w, r, sessions)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, sessions []post.AutoStopped) {
+	render.JSON(w, r, Response{
+		Sessions: sessions,
+	})
+}
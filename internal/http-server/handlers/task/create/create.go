@@ -5,16 +5,33 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"log/slog"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/estimate"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
 )
 
 type Request struct {
 	UserId      int    `json:"user_id" validate:"required"`
 	Description string `json:"description" validate:"required"`
+	ProjectId   *int   `json:"project_id,omitempty"`
+	ParentId    *int   `json:"parent_id,omitempty"`
+	Billable    bool   `json:"billable,omitempty"`
+	// EstimateMinutes is the planned duration of the task.
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// StartTime and EndTime record a finished time entry for past work.
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	AllowOverlap bool       `json:"allow_overlap,omitempty"`
 }
 
 type Response struct {
@@ -22,19 +39,22 @@ type Response struct {
 }
 
 type TaskCreate interface {
-	CreateTask(ctx context.Context, userId int, description string) (int, error)
+	CreateTask(ctx context.Context, task post.NewTask) (int, error)
+	CreateTimeEntry(ctx context.Context, task post.NewTask, startTime, endTime time.Time, allowOverlap bool) (int, error)
+	CheckEstimates(ctx context.Context, taskId *int, thresholds []int, now time.Time) ([]post.EstimateAlert, error)
 }
 
 // @Summary Создать task
-// @Description создать task по user_id и description
+// @Description создать task по user_id, description, project_id, parent_id - task, подзадачей которого он будет, и estimate_minutes - оценка в минутах, с start_time и end_time создается завершенная запись времени, при превышении порогов оценки task отмечается
 // @ID create-task-by-user_id-description
 // @Accept  json
 // @Produce  json
 // @Success 200 {int} id "ok"
 // @Failure 400 {string} string "empty body"
 // @Failure 404 {string} string "not save task"
+// @Failure 409 {object} response.Response "time entry overlaps other entries or period is locked by an approved timesheet"
 // @Router /task [post]
-func New(context context.Context, log *slog.Logger, taskCreate TaskCreate) http.HandlerFunc {
+func New(context context.Context, log *slog.Logger, taskCreate TaskCreate, estimateThresholds []int) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.task.create.New"
@@ -55,23 +75,96 @@ func New(context context.Context, log *slog.Logger, taskCreate TaskCreate) http.
 		}
 
 		if err != nil {
-			log.Error("failed to decode request body", err)
+			log.Error("failed to decode request body", sl.Err(err))
 			http.Error(w, "error", http.StatusBadRequest)
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		id, err := taskCreate.CreateTask(context, req.UserId, req.Description)
+		if (req.StartTime == nil) != (req.EndTime == nil) {
+			log.Info("time entry without start_time or end_time")
+			http.Error(w, "start_time and end_time must be set together", http.StatusBadRequest)
+			return
+		}
+
+		if req.EstimateMinutes != nil && *req.EstimateMinutes <= 0 {
+			log.Info("invalid estimate", slog.Int("estimate_minutes", *req.EstimateMinutes))
+			http.Error(w, "estimate_minutes must be positive", http.StatusBadRequest)
+			return
+		}
+
+		task := post.NewTask{
+			UserId:          req.UserId,
+			Description:     req.Description,
+			ProjectId:       req.ProjectId,
+			ParentId:        req.ParentId,
+			Billable:        req.Billable,
+			EstimateMinutes: req.EstimateMinutes,
+		}
+
+		var id int
+
+		if req.StartTime != nil {
+			if err := interval.Validate(*req.StartTime, *req.EndTime, time.Now()); err != nil {
+				log.Info("invalid time entry", sl.Err(err))
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			id, err = taskCreate.CreateTimeEntry(context, task, *req.StartTime, *req.EndTime, req.AllowOverlap)
+		} else {
+			id, err = taskCreate.CreateTask(context, task)
+		}
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("user_id", req.UserId))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrOverlap) {
+			log.Info("time entry overlaps", slog.Int("user_id", req.UserId))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "time_entry_overlap")
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrProjectNotFound) {
+			log.Info("project not found", slog.Any("project_id", req.ProjectId))
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrParentNotFound) {
+			log.Info("parent task not found", slog.Any("parent_id", req.ParentId))
+			http.Error(w, "parent task not found", http.StatusNotFound)
+			return
+		}
 
 		if err != nil {
-			log.Error("failed to add task", err)
+			log.Error("failed to add task", sl.Err(err))
 			http.Error(w, "not save task", http.StatusInternalServerError)
 			return
 		}
 
 		log.Info("task added", slog.Int("id", id))
 
+		// a time entry may exceed its estimate already, a failed check is left to the next one
+		if req.StartTime != nil && req.EstimateMinutes != nil {
+			alerts, err := taskCreate.CheckEstimates(context, &id, estimateThresholds, time.Now())
+			if err != nil {
+				log.Error("failed to check estimate", sl.Err(err))
+			}
+
+			estimate.Log(log, alerts)
+		}
+
 		responseOK(w, r, id)
 	}
 }
@@ -0,0 +1,100 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id        int `json:"id" validate:"required"`
+	ChangedBy int `json:"changed_by" validate:"required"`
+}
+
+type TaskDelete interface {
+	DeleteTask(ctx context.Context, id int, changedBy int) error
+}
+
+// @Summary Удалить task
+// @Description удалить task по id вместе с сессиями, удаление пишется в audit
+// @ID delete-task-by-id
+// @Accept  json
+// @Produce text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't task or changed_by user not found"
+// @Failure 409 {object} response.Response "period is locked by an approved timesheet"
+// @Router /task [delete]
+func New(context context.Context, log *slog.Logger, taskDelete TaskDelete) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.delete.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.ChangedBy == 0 {
+			log.Info("changed_by is empty")
+			http.Error(w, "changed_by is required", http.StatusBadRequest)
+			return
+		}
+
+		err = taskDelete.DeleteTask(context, req.Id, req.ChangedBy)
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("id", req.Id))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int("id", req.Id))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("changed_by user not found", slog.Int("changed_by", req.ChangedBy))
+			http.Error(w, "changed_by user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to delete task", sl.Err(err))
+			http.Error(w, "not delete task", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("task delete", slog.Int("id", req.Id), slog.Int("changed_by", req.ChangedBy))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
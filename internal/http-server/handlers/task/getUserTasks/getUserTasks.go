@@ -12,33 +12,63 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage"
 	"time_tracker/internal/storage/post"
 )
 
+// MaxDays limits the period of an XLSX timesheet, which has a row per day.
+const MaxDays = 366
+
 type Request struct {
 	UserId      int       `json:"user_id"`
 	StartPeriod time.Time `json:"startPeriod"`
 	EndPeriod   time.Time `json:"endPeriod"`
+	ProjectIds  []int     `json:"project_ids,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	// GroupBy adds totals per project or per tag to the per task report.
+	GroupBy string `json:"group_by,omitempty"`
+	// From and To are YYYY-MM-DD days in the time zone of the user, both included,
+	// used instead of startPeriod and endPeriod.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// IncludeRunning counts running tasks up to now.
+	IncludeRunning bool `json:"include_running,omitempty"`
 }
 
 type Response struct {
 	TaskTimes []post.TaskTime `json:"task_time,omitempty"`
+	Groups    []report.Group  `json:"groups,omitempty"`
+	Billing   report.Billing  `json:"billing"`
+	// TotalSeconds is the exact time of all tasks in the period.
+	TotalSeconds float64 `json:"total_seconds"`
 }
 
 type UserTaskTimeGet interface {
-	GetUserTaskTime(ctx context.Context, user_id int, startPeriod, endPeriod time.Time) ([]post.TaskTime, error)
+	GetUserTaskTime(ctx context.Context, filter post.ReportFilter) ([]post.TaskTime, error)
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	GetUserSessions(ctx context.Context, filter post.ReportFilter) ([]post.SessionTime, error)
+	GetPartTimeFactor(ctx context.Context, id int) (float64, error)
+	GetApprovedAbsences(ctx context.Context, userId int, start, end time.Time) ([]post.Absence, error)
 }
 
 // @Summary Получить userTaskTime
-// @Description получить userTaskTime по user_id и startPerio, endPeriod
+// @Description получить userTaskTime по user_id и startPerio, endPeriod или дням from, to в часовом поясе user, сессии обрезаются по границам периода, include_running учитывает запущенные task до текущего момента, время task суммируется по всем сессиям, фильтры project_ids и tags, group_by=project или tag, billing - billable время и сумма по ставкам; в xlsx без фильтров утвержденные отсутствия засчитываются в столбец Credited по рабочему календарю и part_time_factor user
 // @ID get-user_task_time-by-user_id-startPeriod-endPeriod
 // @Accept  json
-// @Produce  json
+// @Produce  json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "json, csv или xlsx - табель по дням и task, по умолчанию по заголовку Accept; csv с group_by - итоги по project или tag"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
 // @Success 200 {array} post.TaskTime "ok"
 // @Failure 400 {string} string "empty body"
 // @Failure 404 {string} string "failed to get user_task_time"
 // @Router //task/task-time [get]
-func New(context context.Context, log *slog.Logger, userTaskTimeGet UserTaskTimeGet) http.HandlerFunc {
+func New(context context.Context, log *slog.Logger, userTaskTimeGet UserTaskTimeGet, currency string, csvExport export.CSV, cal *workcal.Calendar) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.taks.getTaskTime.New"
 
@@ -47,9 +77,23 @@ func New(context context.Context, log *slog.Logger, userTaskTimeGet UserTaskTime
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		var req Request
 
-		err := render.DecodeJSON(r.Body, &req)
+		err = render.DecodeJSON(r.Body, &req)
 
 		if errors.Is(err, io.EOF) {
 			log.Error("request body is empty")
@@ -58,28 +102,152 @@ func New(context context.Context, log *slog.Logger, userTaskTimeGet UserTaskTime
 		}
 
 		if err != nil {
-			log.Error("failed to decode request body", err)
+			log.Error("failed to decode request body", sl.Err(err))
 			http.Error(w, "error", http.StatusBadRequest)
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		taskTimes, err := userTaskTimeGet.GetUserTaskTime(context, req.UserId, req.StartPeriod, req.EndPeriod)
+		switch req.GroupBy {
+		case "", report.GroupByTask, report.GroupByProject, report.GroupByTag:
+		default:
+			log.Info("invalid group_by", slog.String("group_by", req.GroupBy))
+			http.Error(w, "group_by must be task, project or tag", http.StatusBadRequest)
+			return
+		}
+
+		start, end := req.StartPeriod, req.EndPeriod
+		loc := time.UTC
+
+		// days of the period and of the timesheet are counted in the time zone of the user
+		if req.From != "" || req.To != "" || respFormat == format.XLSX {
+			loc, err = userTaskTimeGet.GetUserLocation(context, req.UserId)
+
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Info("user not found", slog.Int("user_id", req.UserId))
+				http.Error(w, "user not found", http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				log.Error("failed to get user time zone", sl.Err(err))
+				http.Error(w, "error to DB", http.StatusInternalServerError)
+				return
+			}
+
+		}
+
+		if req.From != "" || req.To != "" {
+			start, end, err = interval.Days(req.From, req.To, loc)
+			if err != nil {
+				log.Info("invalid period", sl.Err(err))
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		filter := post.ReportFilter{
+			UserId:         req.UserId,
+			StartPeriod:    start,
+			EndPeriod:      end,
+			ProjectIds:     req.ProjectIds,
+			Tags:           req.Tags,
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		}
+
+		if respFormat == format.XLSX {
+			if !end.After(start) {
+				log.Info("xlsx without period")
+				http.Error(w, "from and to or startPeriod and endPeriod are required", http.StatusBadRequest)
+				return
+			}
+
+			if end.Sub(start) > MaxDays*24*time.Hour {
+				log.Info("period too long", slog.Time("start", start), slog.Time("end", end))
+				http.Error(w, "period must not be longer than a year", http.StatusBadRequest)
+				return
+			}
+
+			sessions, err := userTaskTimeGet.GetUserSessions(context, filter)
+			if err != nil {
+				log.Error("failed to get user sessions", sl.Err(err))
+				http.Error(w, "error to DB", http.StatusInternalServerError)
+				return
+			}
+
+			timesheet := report.Timesheet{
+				UserId: req.UserId,
+				Rows:   report.Summary(sessions, report.PeriodDay, start.In(loc), end.In(loc)),
+			}
+
+			// absences are time of the user, not of projects or tags
+			if len(req.ProjectIds) == 0 && len(req.Tags) == 0 {
+				factor, err := userTaskTimeGet.GetPartTimeFactor(context, req.UserId)
+				if err != nil {
+					log.Error("failed to get part-time factor", sl.Err(err))
+					http.Error(w, "error to DB", http.StatusInternalServerError)
+					return
+				}
+
+				absences, err := userTaskTimeGet.GetApprovedAbsences(context, req.UserId, start.In(loc), end.In(loc))
+				if err != nil {
+					log.Error("failed to get user absences", sl.Err(err))
+					http.Error(w, "error to DB", http.StatusInternalServerError)
+					return
+				}
+
+				report.Credit(timesheet.Rows, report.AbsenceCredit(absences, cal, factor))
+			}
+
+			if err := export.WriteXLSX(w, "task-time", []report.Timesheet{timesheet}); err != nil {
+				log.Error("failed to write xlsx", sl.Err(err))
+				http.Error(w, "failed to write xlsx", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		taskTimes, err := userTaskTimeGet.GetUserTaskTime(context, filter)
 		if err != nil {
-			log.Error("failed to get user_task_time", err)
+			log.Error("failed to get user_task_time", sl.Err(err))
 			http.Error(w, "error to DB", http.StatusInternalServerError)
 			return
 		}
 
 		log.Info("userTaskTime get", slog.Any("user_id", req.UserId))
 
-		responseOK(w, r, taskTimes)
+		var groups []report.Group
+		switch req.GroupBy {
+		case report.GroupByProject:
+			groups = report.ByProject(taskTimes)
+		case report.GroupByTag:
+			groups = report.ByTag(taskTimes)
+		}
+
+		if respFormat == format.CSV {
+			// the task rows carry billable and amount, grouped reports get a row per group
+			if groups != nil {
+				err = export.WriteGroups(csvOut.Start(w, "task-time-"+req.GroupBy), req.GroupBy, groups)
+			} else {
+				err = export.WriteTaskTimes(csvOut.Start(w, "task-time"), taskTimes)
+			}
+
+			if err != nil {
+				log.Error("failed to write csv", sl.Err(err))
+			}
+			return
+		}
+
+		responseOK(w, r, taskTimes, groups, report.Bill(taskTimes, currency), report.TotalSeconds(taskTimes))
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request, taskTimes []post.TaskTime) {
+func responseOK(w http.ResponseWriter, r *http.Request, taskTimes []post.TaskTime, groups []report.Group, billing report.Billing, totalSeconds float64) {
 	render.JSON(w, r, Response{
-		TaskTimes: taskTimes,
+		TaskTimes:    taskTimes,
+		Groups:       groups,
+		Billing:      billing,
+		TotalSeconds: totalSeconds,
 	})
 }
@@ -0,0 +1,139 @@
+package icsImport
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/icsimport"
+	"time_tracker/internal/lib/ical"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+// MaxFileSize limits the size of an uploaded calendar.
+const MaxFileSize = 10 << 20
+
+// @Summary Импортировать task из календаря
+// @Description создать завершенные task user_id из событий файла .ics: category и keyword оставляют события с категорией или словом в summary и description, project_id и billable задаются всем task; события, импортированные ранее (по UID), пропускаются, пересекающиеся с другими записями времени возвращаются в conflicting, если не задан allow_overlap
+// @ID post-task-import
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "файл .ics"
+// @Param user_id formData int true "id user"
+// @Param category formData string false "категория событий"
+// @Param keyword formData string false "слово в summary или description событий"
+// @Param project_id formData int false "project task"
+// @Param billable formData bool false "task оплачиваемые"
+// @Param allow_overlap formData bool false "разрешить пересечения с другими записями времени"
+// @Success 200 {object} icsimport.Report "ok"
+// @Failure 400 {string} string "invalid calendar"
+// @Failure 404 {string} string "user not found"
+// @Failure 500 {string} string "error to DB"
+// @Router /task/import [post]
+func New(context context.Context, log *slog.Logger, timeEntryCreate icsimport.TimeEntryCreate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.icsImport.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxFileSize)
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			log.Info("failed to read uploaded file", sl.Err(err))
+			http.Error(w, "file is required", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		opts, err := options(r)
+		if err != nil {
+			log.Info("invalid form", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Info("form decoded", slog.Any("options", opts))
+
+		report, err := icsimport.Import(context, timeEntryCreate, file, opts)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", opts.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrProjectNotFound) {
+			log.Info("project not found", slog.Any("project_id", opts.ProjectId))
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, ical.ErrNotCalendar) {
+			log.Info("invalid calendar", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to import calendar", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("calendar imported",
+			slog.Int("user_id", opts.UserId),
+			slog.Int("created", len(report.Created)),
+			slog.Int("skipped", len(report.Skipped)),
+			slog.Int("conflicting", len(report.Conflicting)),
+		)
+
+		render.JSON(w, r, report)
+	}
+}
+
+func options(r *http.Request) (icsimport.Options, error) {
+	opts := icsimport.Options{
+		Category: r.FormValue("category"),
+		Keyword:  r.FormValue("keyword"),
+		Now:      time.Now(),
+	}
+
+	var err error
+
+	if opts.UserId, err = strconv.Atoi(r.FormValue("user_id")); err != nil {
+		return opts, errors.New("user_id is required")
+	}
+
+	if v := r.FormValue("project_id"); v != "" {
+		projectId, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.New("project_id must be a number")
+		}
+		opts.ProjectId = &projectId
+	}
+
+	if v := r.FormValue("billable"); v != "" {
+		if opts.Billable, err = strconv.ParseBool(v); err != nil {
+			return opts, errors.New("billable must be true or false")
+		}
+	}
+
+	if v := r.FormValue("allow_overlap"); v != "" {
+		if opts.AllowOverlap, err = strconv.ParseBool(v); err != nil {
+			return opts, errors.New("allow_overlap must be true or false")
+		}
+	}
+
+	return opts, nil
+}
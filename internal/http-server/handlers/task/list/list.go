@@ -0,0 +1,197 @@
+package list
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId      *int             `json:"user_id"`
+	Status      *post.TaskStatus `json:"status"`
+	From        *time.Time       `json:"from"`
+	To          *time.Time       `json:"to"`
+	Description *string          `json:"description"`
+	// SortBy is start_time or duration, Order is asc or desc.
+	SortBy string `json:"sort_by"`
+	Order  string `json:"order"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+type Response struct {
+	Tasks      []post.TaskRow `json:"tasks"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type TasksList interface {
+	ListTasks(ctx context.Context, filter post.TaskFilter, now time.Time) ([]post.TaskRow, string, error)
+	StreamTasks(ctx context.Context, filter post.TaskFilter, now time.Time, fn func(post.TaskRow) error) error
+}
+
+// @Summary Получить список task
+// @Description получить task с фильтрацией по user, status, периоду и подстроке description, сортировкой по start_time или duration и keyset пагинацией через cursor, в CSV выгружаются все task после cursor без limit
+// @ID get-tasks
+// @Accept  json
+// @Produce  json,text/csv
+// @Param format query string false "json или csv, по умолчанию по заголовку Accept"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /tasks [get]
+func New(context context.Context, log *slog.Logger, tasksList TasksList, csvExport export.CSV) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Order != "" && req.Order != "asc" && req.Order != "desc" {
+			log.Info("invalid order", slog.String("order", req.Order))
+			http.Error(w, "order must be asc or desc", http.StatusBadRequest)
+			return
+		}
+
+		filter := post.TaskFilter{
+			UserId:      req.UserId,
+			Status:      req.Status,
+			From:        req.From,
+			To:          req.To,
+			Description: req.Description,
+			SortBy:      req.SortBy,
+			Desc:        req.Order == "desc",
+			Cursor:      req.Cursor,
+			Limit:       req.Limit,
+		}
+
+		if respFormat == format.CSV {
+			streamCSV(context, log, w, tasksList, filter, csvOut)
+			return
+		}
+
+		tasks, next, err := tasksList.ListTasks(context, filter, time.Now())
+
+		if errors.Is(err, storage.ErrInvalidCursor) || errors.Is(err, storage.ErrInvalidSort) {
+			log.Info("invalid list request", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to list tasks", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("tasks list", slog.Int("count", len(tasks)))
+
+		responseOK(w, r, tasks, next)
+	}
+}
+
+// streamCSV writes the tasks as they are read. Once the first row is sent the status
+// can not change any more, so later errors are only logged.
+func streamCSV(ctx context.Context, log *slog.Logger, w http.ResponseWriter, tasksList TasksList, filter post.TaskFilter, csvOut export.CSV) {
+	var cw *csv.Writer
+	count := 0
+
+	err := tasksList.StreamTasks(ctx, filter, time.Now(), func(row post.TaskRow) error {
+		if cw == nil {
+			cw = csvOut.Start(w, "tasks")
+			cw.Write(export.TaskRowHeader)
+		}
+
+		count++
+
+		return cw.Write(export.TaskRowRecord(row))
+	})
+
+	if cw == nil {
+		if errors.Is(err, storage.ErrInvalidCursor) || errors.Is(err, storage.ErrInvalidSort) {
+			log.Info("invalid list request", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to list tasks", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		cw = csvOut.Start(w, "tasks")
+		cw.Write(export.TaskRowHeader)
+	}
+
+	cw.Flush()
+
+	if err == nil {
+		err = cw.Error()
+	}
+
+	if err != nil {
+		log.Error("failed to stream tasks", sl.Err(err))
+		return
+	}
+
+	log.Info("tasks list", slog.Int("count", count))
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, tasks []post.TaskRow, next string) {
+	if tasks == nil {
+		tasks = []post.TaskRow{}
+	}
+
+	render.JSON(w, r, Response{
+		Tasks:      tasks,
+		NextCursor: next,
+	})
+}
@@ -0,0 +1,87 @@
+package running
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+}
+
+type Response struct {
+	Tasks []post.RunningTask `json:"tasks"`
+}
+
+type RunningTasksGet interface {
+	GetRunningTasks(ctx context.Context, userId int, now time.Time) ([]post.RunningTask, error)
+}
+
+// @Summary Получить запущенные task
+// @Description получить запущенные task user с description, start_time текущей сессии и прошедшим временем, посчитанным на сервере
+// @ID get-running-tasks-by-user_id
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /task/running [get]
+func New(context context.Context, log *slog.Logger, runningTasksGet RunningTasksGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.running.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		tasks, err := runningTasksGet.GetRunningTasks(context, req.UserId, time.Now())
+		if err != nil {
+			log.Error("failed to get running tasks", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("running tasks get", slog.Int("user_id", req.UserId), slog.Int("count", len(tasks)))
+
+		responseOK(w, r, tasks)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, tasks []post.RunningTask) {
+	if tasks == nil {
+		tasks = []post.RunningTask{}
+	}
+
+	render.JSON(w, r, Response{
+		Tasks: tasks,
+	})
+}
@@ -10,26 +10,35 @@ import (
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
 )
 
 type Request struct {
 	Id int `json:"id" validate:"required"`
 }
 
+type Response struct {
+	AutoStopped []int `json:"auto_stopped,omitempty"`
+}
+
 type TaskStart interface {
-	BeginTask(ctx context.Context, id int, startTime time.Time) error
+	BeginTask(ctx context.Context, id int, startTime time.Time, stopOthers bool) ([]int, error)
 }
 
 // @Summary Начать task time
-// @Description начать отчет времени task, поле start_time
+// @Description начать отчет времени task, открывает новую сессию task, task должен быть в статусе created или paused, при single_active_timer останавливает другие task user
 // @ID put-task-of-start_time
 // @Accept  json
-// @Produce  text/plain
-// @Success 200 "ok"
+// @Produce  json
+// @Success 200 {object} Response "ok, auto_stopped - task остановленные автоматически"
 // @Failure 400 {string} string "empty body"
 // @Failure 404 {string} string "have't task"
+// @Failure 409 {object} response.Response "illegal status transition or period is locked by an approved timesheet"
 // @Router /task/start [put]
-func New(context context.Context, log *slog.Logger, taskStart TaskStart) http.HandlerFunc {
+func New(context context.Context, log *slog.Logger, taskStart TaskStart, singleActiveTimer bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.task.start.New"
 
@@ -49,23 +58,48 @@ func New(context context.Context, log *slog.Logger, taskStart TaskStart) http.Ha
 		}
 
 		if err != nil {
-			log.Error("failed to decode request body", err)
+			log.Error("failed to decode request body", sl.Err(err))
 			http.Error(w, "error", http.StatusBadRequest)
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		err = taskStart.BeginTask(context, req.Id, time.Now())
+		autoStopped, err := taskStart.BeginTask(context, req.Id, time.Now(), singleActiveTimer)
+
+		var transitionErr *storage.TransitionError
+		if errors.As(err, &transitionErr) {
+			log.Info("illegal task transition", slog.Int("id", req.Id), slog.String("reason", transitionErr.Reason))
+			resp.Error(w, r, http.StatusConflict, transitionErr.Error(), transitionErr.Reason)
+			return
+		}
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("id", req.Id))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int("id", req.Id))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
 
 		if err != nil {
-			log.Error("failed to start task", err)
+			log.Error("failed to start task", sl.Err(err))
 			http.Error(w, "have't task", http.StatusInternalServerError)
 			return
 		}
 
-		log.Info("start task", slog.Int("id", req.Id))
+		log.Info("start task", slog.Int("id", req.Id), slog.Any("auto_stopped", autoStopped))
 
-		w.WriteHeader(http.StatusOK)
+		responseOK(w, r, autoStopped)
 	}
 }
+
+func responseOK(w http.ResponseWriter, r *http.Request, autoStopped []int) {
+	render.JSON(w, r, Response{
+		AutoStopped: autoStopped,
+	})
+}
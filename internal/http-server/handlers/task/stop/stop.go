@@ -10,26 +10,35 @@ import (
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/estimate"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
 )
 
 type Request struct {
-	Id int `json:"id" validate:"required"`
+	Id   int  `json:"id" validate:"required"`
+	Done bool `json:"done"`
 }
 
 type TaskStop interface {
-	StopTask(ctx context.Context, id int, endTime time.Time) error
+	StopTask(ctx context.Context, id int, endTime time.Time, done bool) error
+	CheckEstimates(ctx context.Context, taskId *int, thresholds []int, now time.Time) ([]post.EstimateAlert, error)
 }
 
 // @Summary Остановить task time
-// @Description остановить отчет времени task, поле stop_time
+// @Description остановить отчет времени task, закрывает открытую сессию task и ставит task на паузу, done завершает task, при превышении порогов оценки task отмечается
 // @ID put-task-of-stop_time
 // @Accept  json
 // @Produce  text/plain
 // @Success 200 "ok"
 // @Failure 400 {string} string "empty body"
 // @Failure 404 {string} string "have't task"
+// @Failure 409 {object} response.Response "illegal status transition or period is locked by an approved timesheet"
 // @Router /task/stop [put]
-func New(context context.Context, log *slog.Logger, taskStop TaskStop) http.HandlerFunc {
+func New(context context.Context, log *slog.Logger, taskStop TaskStop, estimateThresholds []int) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.task.stop.New"
 
@@ -49,23 +58,52 @@ func New(context context.Context, log *slog.Logger, taskStop TaskStop) http.Hand
 		}
 
 		if err != nil {
-			log.Error("failed to decode request body", err)
+			log.Error("failed to decode request body", sl.Err(err))
 			http.Error(w, "error", http.StatusBadRequest)
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		err = taskStop.StopTask(context, req.Id, time.Now())
+		now := time.Now()
+
+		err = taskStop.StopTask(context, req.Id, now, req.Done)
+
+		var transitionErr *storage.TransitionError
+		if errors.As(err, &transitionErr) {
+			log.Info("illegal task transition", slog.Int("id", req.Id), slog.String("reason", transitionErr.Reason))
+			resp.Error(w, r, http.StatusConflict, transitionErr.Error(), transitionErr.Reason)
+			return
+		}
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("id", req.Id))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int("id", req.Id))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
 
 		if err != nil {
-			log.Error("failed to stop task", err)
+			log.Error("failed to stop task", sl.Err(err))
 			http.Error(w, "have't task", http.StatusInternalServerError)
 			return
 		}
 
 		log.Info("stop task", slog.Int("id", req.Id))
 
+		// the task is stopped already, a failed check is left to the next one
+		alerts, err := taskStop.CheckEstimates(context, &req.Id, estimateThresholds, now)
+		if err != nil {
+			log.Error("failed to check estimate", sl.Err(err))
+		}
+
+		estimate.Log(log, alerts)
+
 		w.WriteHeader(http.StatusOK)
 	}
 }
@@ -0,0 +1,213 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/api/format"
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+// MaxDays limits the period of a summary.
+const MaxDays = 366
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+	// From and To are YYYY-MM-DD days in the time zone of the user, both included.
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+	// Period is day, week or month, day if omitted.
+	Period         string   `json:"period,omitempty"`
+	ProjectIds     []int    `json:"project_ids,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	IncludeRunning bool     `json:"include_running,omitempty"`
+}
+
+type Response struct {
+	Buckets      []report.Bucket `json:"buckets,omitempty"`
+	TotalSeconds float64         `json:"total_seconds"`
+	// TotalCreditedSeconds is the time credited for approved absences, not part of TotalSeconds.
+	TotalCreditedSeconds float64 `json:"total_credited_seconds,omitempty"`
+}
+
+type SummaryGet interface {
+	GetUserSessions(ctx context.Context, filter post.ReportFilter) ([]post.SessionTime, error)
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	GetPartTimeFactor(ctx context.Context, id int) (float64, error)
+	GetApprovedAbsences(ctx context.Context, userId int, start, end time.Time) ([]post.Absence, error)
+}
+
+// @Summary Получить сводку времени user
+// @Description получить время user за дни from, to по дням, ISO неделям или месяцам (period=day, week, month) в часовом поясе user: итог каждого периода и время по description и project_id, фильтры project_ids и tags, include_running учитывает запущенные task; без фильтров утвержденные отсутствия засчитываются в credited_seconds по рабочему календарю и part_time_factor user
+// @ID get-task-summary
+// @Accept  json
+// @Produce  json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "json, csv или xlsx - табель по периодам и task, по умолчанию по заголовку Accept"
+// @Param delimiter query string false "разделитель CSV, один символ или tab"
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user not found"
+// @Failure 500 {string} string "error to DB"
+// @Router /task/summary [get]
+func New(context context.Context, log *slog.Logger, summaryGet SummaryGet, csvExport export.CSV, cal *workcal.Calendar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.summary.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		respFormat, err := format.Negotiate(r)
+		if err != nil {
+			log.Info("unsupported format", slog.String("format", r.URL.Query().Get("format")))
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		csvOut, err := csvExport.ForRequest(r)
+		if err != nil {
+			log.Info("invalid csv delimiter", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		switch req.Period {
+		case "":
+			req.Period = report.PeriodDay
+		case report.PeriodDay, report.PeriodWeek, report.PeriodMonth:
+		default:
+			log.Info("invalid period", slog.String("period", req.Period))
+			http.Error(w, "period must be day, week or month", http.StatusBadRequest)
+			return
+		}
+
+		loc, err := summaryGet.GetUserLocation(context, req.UserId)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user time zone", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		start, end, err := interval.Days(req.From, req.To, loc)
+		if err != nil {
+			log.Info("invalid period", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if end.Sub(start) > MaxDays*24*time.Hour {
+			log.Info("period too long", slog.String("from", req.From), slog.String("to", req.To))
+			http.Error(w, "period must not be longer than a year", http.StatusBadRequest)
+			return
+		}
+
+		sessions, err := summaryGet.GetUserSessions(context, post.ReportFilter{
+			UserId:         req.UserId,
+			StartPeriod:    start,
+			EndPeriod:      end,
+			ProjectIds:     req.ProjectIds,
+			Tags:           req.Tags,
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to get user sessions", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		buckets := report.Summary(sessions, req.Period, start, end)
+
+		// absences are time of the user, not of projects or tags
+		if len(req.ProjectIds) == 0 && len(req.Tags) == 0 {
+			factor, err := summaryGet.GetPartTimeFactor(context, req.UserId)
+			if err != nil {
+				log.Error("failed to get part-time factor", sl.Err(err))
+				http.Error(w, "error to DB", http.StatusInternalServerError)
+				return
+			}
+
+			absences, err := summaryGet.GetApprovedAbsences(context, req.UserId, start, end)
+			if err != nil {
+				log.Error("failed to get user absences", sl.Err(err))
+				http.Error(w, "error to DB", http.StatusInternalServerError)
+				return
+			}
+
+			report.Credit(buckets, report.AbsenceCredit(absences, cal, factor))
+		}
+
+		switch respFormat {
+		case format.CSV:
+			if err := export.WriteSummary(csvOut.Start(w, "summary"), buckets); err != nil {
+				log.Error("failed to write csv", sl.Err(err))
+			}
+			return
+		case format.XLSX:
+			timesheet := report.Timesheet{UserId: req.UserId, Rows: buckets}
+
+			if err := export.WriteXLSX(w, "summary", []report.Timesheet{timesheet}); err != nil {
+				log.Error("failed to write xlsx", sl.Err(err))
+				http.Error(w, "failed to write xlsx", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		var total, credited float64
+		for _, b := range buckets {
+			total += b.Seconds
+			credited += b.CreditedSeconds
+		}
+
+		log.Info("summary get", slog.Int("user_id", req.UserId), slog.Int("buckets", len(buckets)))
+
+		responseOK(w, r, buckets, total, credited)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, buckets []report.Bucket, totalSeconds, creditedSeconds float64) {
+	render.JSON(w, r, Response{
+		Buckets:              buckets,
+		TotalSeconds:         totalSeconds,
+		TotalCreditedSeconds: creditedSeconds,
+	})
+}
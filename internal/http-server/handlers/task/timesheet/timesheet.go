@@ -0,0 +1,154 @@
+package timesheet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/export"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+	// Month is YYYY-MM in the time zone of the user.
+	Month          string `json:"month" validate:"required"`
+	IncludeRunning bool   `json:"include_running,omitempty"`
+}
+
+type TimesheetGet interface {
+	GetUserById(ctx context.Context, id int) (post.User, error)
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	GetUserSessions(ctx context.Context, filter post.ReportFilter) ([]post.SessionTime, error)
+	GetPartTimeFactor(ctx context.Context, id int) (float64, error)
+	GetApprovedAbsences(ctx context.Context, userId int, start, end time.Time) ([]post.Absence, error)
+}
+
+// @Summary Получить табель user за месяц в PDF
+// @Description получить табель user за месяц month (YYYY-MM) в часовом поясе user для подписи: фамилия, имя и отчество user, время по дням и task, утвержденные отсутствия по рабочему календарю и part_time_factor user, итоги дней и месяца, строки для подписей, include_running учитывает запущенные task
+// @ID get-task-timesheet
+// @Accept  json
+// @Produce  application/pdf
+// @Success 200 {file} file "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user not found"
+// @Failure 500 {string} string "error to DB"
+// @Failure 503 {string} string "pdf font is not configured"
+// @Router /task/timesheet [get]
+func New(context context.Context, log *slog.Logger, timesheetGet TimesheetGet, pdfExport export.PDF, cal *workcal.Calendar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.timesheet.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if !pdfExport.Enabled() {
+			log.Error("pdf font is not configured")
+			http.Error(w, export.ErrNoPDFFont.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		user, err := timesheetGet.GetUserById(context, req.UserId)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		loc, err := timesheetGet.GetUserLocation(context, req.UserId)
+		if err != nil {
+			log.Error("failed to get user time zone", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		start, end, err := interval.Month(req.Month, loc)
+		if err != nil {
+			log.Info("invalid month", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sessions, err := timesheetGet.GetUserSessions(context, post.ReportFilter{
+			UserId:         req.UserId,
+			StartPeriod:    start,
+			EndPeriod:      end,
+			IncludeRunning: req.IncludeRunning,
+			Now:            time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to get user sessions", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		factor, err := timesheetGet.GetPartTimeFactor(context, req.UserId)
+		if err != nil {
+			log.Error("failed to get part-time factor", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		absences, err := timesheetGet.GetApprovedAbsences(context, req.UserId, start, end)
+		if err != nil {
+			log.Error("failed to get user absences", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		rows := report.Summary(sessions, report.PeriodDay, start, end)
+		report.Credit(rows, report.AbsenceCredit(absences, cal, factor))
+
+		err = pdfExport.Write(w, "timesheet-"+req.Month, export.PDFTimesheet{
+			FullName: strings.Join(strings.Fields(user.Surname+" "+user.Name+" "+user.Patronymic), " "),
+			Month:    start,
+			Rows:     rows,
+		})
+		if err != nil {
+			log.Error("failed to write pdf", sl.Err(err))
+			http.Error(w, "failed to write pdf", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("timesheet get", slog.Int("user_id", req.UserId), slog.String("month", req.Month))
+	}
+}
@@ -0,0 +1,91 @@
+package tree
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	Id int `json:"id" validate:"required"`
+}
+
+type Response struct {
+	Task *report.Node `json:"task,omitempty"`
+}
+
+type TaskTreeGet interface {
+	GetTaskTree(ctx context.Context, id int) ([]post.TaskNode, error)
+}
+
+// @Summary Получить дерево task
+// @Description получить task со всеми подзадачами: время каждого task и общее время вместе с подзадачами
+// @ID get-task-tree-by-id
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't task"
+// @Router /task/tree [get]
+func New(context context.Context, log *slog.Logger, taskTreeGet TaskTreeGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.tree.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		nodes, err := taskTreeGet.GetTaskTree(context, req.Id)
+
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int("id", req.Id))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get task tree", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("task tree get", slog.Int("id", req.Id), slog.Int("tasks", len(nodes)))
+
+		responseOK(w, r, report.Tree(nodes))
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, task *report.Node) {
+	render.JSON(w, r, Response{
+		Task: task,
+	})
+}
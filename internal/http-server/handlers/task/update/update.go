@@ -0,0 +1,168 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/estimate"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	Id          int     `json:"id" validate:"required"`
+	ChangedBy   int     `json:"changed_by" validate:"required"`
+	Description *string `json:"description,omitempty"`
+	Billable    *bool   `json:"billable,omitempty"`
+	// ParentId moves the task under another task, 0 makes it a top-level task.
+	ParentId *int `json:"parent_id,omitempty"`
+	// EstimateMinutes changes the planned duration, 0 removes it.
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// SessionId selects the session to change, the latest session of the task if omitted.
+	SessionId    int        `json:"session_id,omitempty"`
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	AllowOverlap bool       `json:"allow_overlap,omitempty"`
+}
+
+type TaskUpdate interface {
+	UpdateTask(ctx context.Context, id int, changedBy int, upd post.TaskUpdate, now time.Time) error
+	CheckEstimates(ctx context.Context, taskId *int, thresholds []int, now time.Time) ([]post.EstimateAlert, error)
+}
+
+// @Summary Изменить task
+// @Description изменить description, billable, parent_id (0 - убрать из подзадач), estimate_minutes (0 - убрать оценку), start_time и end_time сессии task, изменения пишутся в audit, отметка превышения порогов оценки пересчитывается
+// @ID patch-task-by-id
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't task or changed_by user not found"
+// @Failure 409 {object} response.Response "time entry overlaps other entries, parent_id makes a cycle or period is locked by an approved timesheet"
+// @Router /task [patch]
+func New(context context.Context, log *slog.Logger, taskUpdate TaskUpdate, estimateThresholds []int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.task.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.ChangedBy == 0 {
+			log.Info("changed_by is empty")
+			http.Error(w, "changed_by is required", http.StatusBadRequest)
+			return
+		}
+
+		if req.EstimateMinutes != nil && *req.EstimateMinutes < 0 {
+			log.Info("invalid estimate", slog.Int("estimate_minutes", *req.EstimateMinutes))
+			http.Error(w, "estimate_minutes must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		now := time.Now()
+
+		err = taskUpdate.UpdateTask(context, req.Id, req.ChangedBy, post.TaskUpdate{
+			Description:     req.Description,
+			Billable:        req.Billable,
+			ParentId:        req.ParentId,
+			EstimateMinutes: req.EstimateMinutes,
+			SessionId:       req.SessionId,
+			StartTime:       req.StartTime,
+			EndTime:         req.EndTime,
+			AllowOverlap:    req.AllowOverlap,
+		}, now)
+
+		if errors.Is(err, interval.ErrEndBeforeStart) || errors.Is(err, interval.ErrInFuture) {
+			log.Info("invalid time entry", sl.Err(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskCycle) {
+			log.Info("task cycle", slog.Int("id", req.Id), slog.Any("parent_id", req.ParentId))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "task_cycle")
+			return
+		}
+
+		if errors.Is(err, storage.ErrParentNotFound) {
+			log.Info("parent task not found", slog.Any("parent_id", req.ParentId))
+			http.Error(w, "parent task not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrPeriodLocked) {
+			log.Info("period locked", slog.Int("id", req.Id))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "period_locked")
+			return
+		}
+
+		if errors.Is(err, storage.ErrOverlap) {
+			log.Info("time entry overlaps", slog.Int("id", req.Id))
+			resp.Error(w, r, http.StatusConflict, err.Error(), "time_entry_overlap")
+			return
+		}
+
+		if errors.Is(err, storage.ErrTaskNotFound) || errors.Is(err, storage.ErrSessionNotFound) {
+			log.Info("task not found", slog.Int("id", req.Id), sl.Err(err))
+			http.Error(w, "have't task", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("changed_by user not found", slog.Int("changed_by", req.ChangedBy))
+			http.Error(w, "changed_by user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to update task", sl.Err(err))
+			http.Error(w, "not update task", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("task update", slog.Int("id", req.Id), slog.Int("changed_by", req.ChangedBy))
+
+		// the estimate or the sessions may have changed, a failed check is left to the next one
+		if req.EstimateMinutes != nil || req.StartTime != nil || req.EndTime != nil {
+			alerts, err := taskUpdate.CheckEstimates(context, &req.Id, estimateThresholds, now)
+			if err != nil {
+				log.Error("failed to check estimate", sl.Err(err))
+			}
+
+			estimate.Log(log, alerts)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
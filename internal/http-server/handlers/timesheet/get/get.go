@@ -0,0 +1,94 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	UserId *int `json:"user_id"`
+	// Status is submitted, approved or rejected.
+	Status *post.TimesheetStatus `json:"status,omitempty"`
+}
+
+type Response struct {
+	Timesheets []post.Timesheet `json:"timesheets,omitempty"`
+}
+
+type TimesheetsGet interface {
+	GetTimesheets(ctx context.Context, userId *int, status *post.TimesheetStatus) ([]post.Timesheet, error)
+}
+
+// @Summary Получить табели
+// @Description получить табели user_id или всех user, со status - только submitted, approved или rejected, например ожидающие утверждения
+// @ID get-timesheets
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 500 {string} string "error to DB"
+// @Router /timesheet [get]
+func New(context context.Context, log *slog.Logger, timesheetsGet TimesheetsGet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.timesheet.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Status != nil {
+			switch *req.Status {
+			case post.TimesheetSubmitted, post.TimesheetApproved, post.TimesheetRejected:
+			default:
+				log.Info("invalid status", slog.String("status", string(*req.Status)))
+				http.Error(w, "status must be submitted, approved or rejected", http.StatusBadRequest)
+				return
+			}
+		}
+
+		timesheets, err := timesheetsGet.GetTimesheets(context, req.UserId, req.Status)
+		if err != nil {
+			log.Error("failed to get timesheets", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("timesheets get", slog.Int("count", len(timesheets)))
+
+		responseOK(w, r, timesheets)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, timesheets []post.Timesheet) {
+	render.JSON(w, r, Response{
+		Timesheets: timesheets,
+	})
+}
@@ -0,0 +1,124 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id         int  `json:"id" validate:"required"`
+	ReviewedBy int  `json:"reviewed_by" validate:"required"`
+	Approve    bool `json:"approve"`
+	// Comment is required to reject a timesheet.
+	Comment *string `json:"comment,omitempty"`
+}
+
+type TimesheetReview interface {
+	ReviewTimesheet(ctx context.Context, id int, reviewedBy int, approve bool, comment *string, now time.Time) error
+}
+
+// @Summary Утвердить или отклонить табель
+// @Description утвердить (approve=true) или отклонить с comment отправленный табель от имени reviewed_by; после утверждения task в периоде табеля нельзя запускать, останавливать, создавать, изменять и удалять
+// @ID put-timesheet-review
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "timesheet not found"
+// @Failure 409 {object} response.Response "timesheet is not submitted, is reviewed by its user or a timer is running"
+// @Router /timesheet/review [put]
+func New(context context.Context, log *slog.Logger, timesheetReview TimesheetReview) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.timesheet.review.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.ReviewedBy == 0 {
+			log.Info("reviewed_by is empty")
+			http.Error(w, "reviewed_by is required", http.StatusBadRequest)
+			return
+		}
+
+		if req.Comment != nil && strings.TrimSpace(*req.Comment) == "" {
+			req.Comment = nil
+		}
+
+		if !req.Approve && req.Comment == nil {
+			log.Info("rejection without comment", slog.Int("id", req.Id))
+			http.Error(w, "comment is required to reject a timesheet", http.StatusBadRequest)
+			return
+		}
+
+		err = timesheetReview.ReviewTimesheet(context, req.Id, req.ReviewedBy, req.Approve, req.Comment, time.Now())
+
+		if errors.Is(err, storage.ErrTimesheetNotFound) {
+			log.Info("timesheet not found", slog.Int("id", req.Id))
+			http.Error(w, "timesheet not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("reviewer not found", slog.Int("reviewed_by", req.ReviewedBy))
+			http.Error(w, "reviewer not found", http.StatusNotFound)
+			return
+		}
+
+		for _, conflict := range []struct {
+			err    error
+			reason string
+		}{
+			{storage.ErrNotSubmitted, "timesheet_not_submitted"},
+			{storage.ErrOwnTimesheet, "own_timesheet"},
+			{storage.ErrTimerRunning, "timer_running"},
+		} {
+			if errors.Is(err, conflict.err) {
+				log.Info("timesheet not reviewed", slog.Int("id", req.Id), sl.Err(err))
+				resp.Error(w, r, http.StatusConflict, err.Error(), conflict.reason)
+				return
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to review timesheet", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("timesheet reviewed", slog.Int("id", req.Id), slog.Int("reviewed_by", req.ReviewedBy), slog.Bool("approve", req.Approve))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
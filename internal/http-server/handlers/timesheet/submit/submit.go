@@ -0,0 +1,144 @@
+package submit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "time_tracker/internal/lib/api/response"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	UserId int `json:"user_id" validate:"required"`
+	// Period is week or month.
+	Period string `json:"period" validate:"required"`
+	// Date is a YYYY-MM-DD day of the period in the time zone of the user.
+	Date string `json:"date" validate:"required"`
+}
+
+type Response struct {
+	Id int `json:"id,omitempty"`
+}
+
+type TimesheetSubmit interface {
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	SubmitTimesheet(ctx context.Context, userId int, period string, start, end time.Time, now time.Time) (int, error)
+}
+
+// @Summary Отправить табель на утверждение
+// @Description отправить на утверждение табель user_id за неделю или месяц (period=week, month), в который входит день date в часовом поясе user; отклоненный табель отправляется снова, у user не должно быть запущенных task в периоде; границы периода (locked_from, locked_to) фиксируются в часовом поясе user при отправке
+// @ID post-timesheet
+// @Accept  json
+// @Produce  json
+// @Success 200 {int} id "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "user not found"
+// @Failure 409 {object} response.Response "timesheet is already submitted or approved, overlaps another timesheet or a timer is running"
+// @Router /timesheet [post]
+func New(context context.Context, log *slog.Logger, timesheetSubmit TimesheetSubmit) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.timesheet.submit.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.Period != report.PeriodWeek && req.Period != report.PeriodMonth {
+			log.Info("invalid period", slog.String("period", req.Period))
+			http.Error(w, "period must be week or month", http.StatusBadRequest)
+			return
+		}
+
+		loc, err := timesheetSubmit.GetUserLocation(context, req.UserId)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user time zone", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		day, err := time.ParseInLocation(interval.DateLayout, req.Date, loc)
+		if err != nil {
+			log.Info("invalid date", slog.String("date", req.Date))
+			http.Error(w, interval.ErrInvalidDate.Error(), http.StatusBadRequest)
+			return
+		}
+
+		start, end := report.PeriodBounds(req.Period, day)
+
+		id, err := timesheetSubmit.SubmitTimesheet(context, req.UserId, req.Period, start, end, time.Now())
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("user_id", req.UserId))
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+
+		for _, conflict := range []struct {
+			err    error
+			reason string
+		}{
+			{storage.ErrTimesheetPending, "timesheet_submitted"},
+			{storage.ErrTimesheetApproved, "timesheet_approved"},
+			{storage.ErrTimesheetOverlap, "timesheet_overlap"},
+			{storage.ErrTimerRunning, "timer_running"},
+		} {
+			if errors.Is(err, conflict.err) {
+				log.Info("timesheet not submitted", slog.Int("user_id", req.UserId), sl.Err(err))
+				resp.Error(w, r, http.StatusConflict, err.Error(), conflict.reason)
+				return
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to submit timesheet", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("timesheet submitted", slog.Int("id", id), slog.Int("user_id", req.UserId))
+
+		responseOK(w, r, id)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, id int) {
+	render.JSON(w, r, Response{
+		Id: id,
+	})
+}
@@ -0,0 +1,92 @@
+package feedToken
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+)
+
+type Request struct {
+	Id int `json:"id" validate:"required"`
+}
+
+type Response struct {
+	Token string `json:"token"`
+	// Path is the path of the calendar feed on this server.
+	Path string `json:"path"`
+}
+
+type FeedTokenRotate interface {
+	RotateFeedToken(ctx context.Context, userId int) (string, error)
+}
+
+// @Summary Создать token календаря user
+// @Description создать новый token календаря user по id, прежний token перестает работать; token показывается один раз, хранится только его хэш
+// @ID put-user-feed-token
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't user"
+// @Router /user/feed-token [put]
+func New(context context.Context, log *slog.Logger, feedTokenRotate FeedTokenRotate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.feedToken.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		token, err := feedTokenRotate.RotateFeedToken(context, req.Id)
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("id", req.Id))
+			http.Error(w, "have't user", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to rotate feed token", sl.Err(err))
+			http.Error(w, "error to DB", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("feed token rotated", slog.Int("id", req.Id))
+
+		responseOK(w, r, token)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, token string) {
+	render.JSON(w, r, Response{
+		Token: token,
+		Path:  "/calendar/" + token + ".ics",
+	})
+}
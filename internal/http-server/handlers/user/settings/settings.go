@@ -0,0 +1,123 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+type Request struct {
+	Id int `json:"id" validate:"required"`
+	// EndOfDay is a HH:MM time after which running timers are stopped, "" removes it.
+	EndOfDay *string `json:"end_of_day,omitempty"`
+	// TimeZone is an IANA time zone name, e.g. Europe/Moscow.
+	TimeZone *string `json:"time_zone,omitempty"`
+	// Department groups users in team reports, "" removes it.
+	Department *string `json:"department,omitempty"`
+	// PartTimeFactor is the share of the standard working hours, 1 for full time.
+	PartTimeFactor *float64 `json:"part_time_factor,omitempty"`
+}
+
+type UserSettingsUpdate interface {
+	UpdateUserSettings(ctx context.Context, id int, settings post.UserSettings) error
+}
+
+// @Summary Изменить настройки user
+// @Description изменить настройки user, переданные в запросе: end_of_day - время остановки запущенных task, time_zone - часовой пояс IANA, в котором считаются дни отчетов, department - отдел user для отчетов по команде, part_time_factor - доля стандартных рабочих часов user от 0 до 1 для отчета о переработках
+// @ID put-user-settings
+// @Accept  json
+// @Produce  text/plain
+// @Success 200 "ok"
+// @Failure 400 {string} string "empty body"
+// @Failure 404 {string} string "have't user"
+// @Router /user/settings [put]
+func New(context context.Context, log *slog.Logger, userSettingsUpdate UserSettingsUpdate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.settings.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+			http.Error(w, "empty body", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if req.EndOfDay != nil && *req.EndOfDay != "" {
+			if _, err := time.Parse("15:04", *req.EndOfDay); err != nil {
+				log.Info("invalid end_of_day", sl.Err(err))
+				http.Error(w, "end_of_day must be a HH:MM time", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if req.TimeZone != nil {
+			// Go resolves "Local" to the zone of the server, Postgres does not know it
+			if _, err := time.LoadLocation(*req.TimeZone); err != nil || *req.TimeZone == "" || *req.TimeZone == "Local" {
+				log.Info("invalid time_zone", slog.String("time_zone", *req.TimeZone))
+				http.Error(w, "time_zone must be an IANA time zone name", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if req.PartTimeFactor != nil && (*req.PartTimeFactor <= 0 || *req.PartTimeFactor > 1) {
+			log.Info("invalid part_time_factor", slog.Float64("part_time_factor", *req.PartTimeFactor))
+			http.Error(w, "part_time_factor must be greater than 0 and at most 1", http.StatusBadRequest)
+			return
+		}
+
+		err = userSettingsUpdate.UpdateUserSettings(context, req.Id, post.UserSettings{
+			EndOfDay:       req.EndOfDay,
+			TimeZone:       req.TimeZone,
+			Department:     req.Department,
+			PartTimeFactor: req.PartTimeFactor,
+		})
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int("id", req.Id))
+			http.Error(w, "have't user", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, storage.ErrInvalidTimeZone) {
+			log.Info("unknown time_zone", slog.String("time_zone", *req.TimeZone))
+			http.Error(w, "time_zone must be an IANA time zone name", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to update user settings", sl.Err(err))
+			http.Error(w, "not update user settings", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("user settings update", slog.Int("id", req.Id))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"log/slog"
@@ -9,7 +10,9 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func New(log *slog.Logger) func(next http.Handler) http.Handler {
+// New logs every request. Paths starting with a secret prefix, like the tokens of
+// calendar feeds, are logged up to the prefix.
+func New(log *slog.Logger, secretPrefixes ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		log := log.With(
 			slog.String("component", "middleware/logger"),
@@ -20,7 +23,7 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			entry := log.With(
 				slog.String("method", r.Method),
-				slog.String("path", r.URL.Path),
+				slog.String("path", redact(r.URL.Path, secretPrefixes)),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
@@ -42,3 +45,32 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(fn)
 	}
 }
+
+// Skip runs next without the logger for paths starting with a secret prefix, for
+// loggers that can not redact them.
+func Skip(logger func(next http.Handler) http.Handler, secretPrefixes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		logged := logger(next)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if redact(r.URL.Path, secretPrefixes) != r.URL.Path {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logged.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func redact(path string, secretPrefixes []string) string {
+	for _, prefix := range secretPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return prefix + "***"
+		}
+	}
+
+	return path
+}
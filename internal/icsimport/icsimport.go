@@ -0,0 +1,183 @@
+// Package icsimport records the events of iCalendar files as time entries of a user.
+package icsimport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"time_tracker/internal/lib/ical"
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/storage"
+	"time_tracker/internal/storage/post"
+)
+
+// Reasons of skipped and conflicting entries.
+const (
+	ReasonAlreadyImported = "already_imported"
+	ReasonFiltered        = "filtered"
+	ReasonAllDay          = "all_day"
+	ReasonRecurring       = "recurring"
+	ReasonCancelled       = "cancelled"
+	ReasonInvalid         = "invalid"
+	ReasonOverlap         = "time_entry_overlap"
+	ReasonPeriodLocked    = "period_locked"
+)
+
+type TimeEntryCreate interface {
+	GetUserLocation(ctx context.Context, id int) (*time.Location, error)
+	CreateTimeEntry(ctx context.Context, task post.NewTask, startTime, endTime time.Time, allowOverlap bool) (int, error)
+}
+
+type Options struct {
+	UserId int
+	// Category keeps the events with the category only, case-insensitively.
+	Category string
+	// Keyword keeps the events with the keyword in the summary or the description only,
+	// case-insensitively.
+	Keyword      string
+	ProjectId    *int
+	Billable     bool
+	AllowOverlap bool
+	Now          time.Time
+}
+
+// Entry is an event of the file and what became of it.
+type Entry struct {
+	UID     string     `json:"uid"`
+	Summary string     `json:"summary"`
+	Start   *time.Time `json:"start,omitempty"`
+	End     *time.Time `json:"end,omitempty"`
+	// TaskId is the task created from the event.
+	TaskId int    `json:"task_id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Error explains an invalid event.
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Created     []Entry `json:"created"`
+	Skipped     []Entry `json:"skipped"`
+	Conflicting []Entry `json:"conflicting"`
+}
+
+// Import creates a finished task with a single session for every event of the file
+// that passes the filters. Events imported before, found by their UID, are skipped,
+// so a file can be imported again after new events are added to it. Floating times
+// of the file are read in the time zone of the user.
+func Import(ctx context.Context, store TimeEntryCreate, r io.Reader, opts Options) (Report, error) {
+	loc, err := store.GetUserLocation(ctx, opts.UserId)
+	if err != nil {
+		return Report{}, err
+	}
+
+	events, err := ical.Parse(r, loc)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Created:     []Entry{},
+		Skipped:     []Entry{},
+		Conflicting: []Entry{},
+	}
+
+	for _, e := range events {
+		entry := Entry{UID: e.UID, Summary: e.Summary}
+		if e.Err == nil {
+			entry.Start, entry.End = &e.Start, &e.End
+		}
+
+		if reason := skipReason(e, opts); reason != "" {
+			entry.Reason = reason
+			if e.Err != nil {
+				entry.Error = e.Err.Error()
+			}
+
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		if err := interval.Validate(e.Start, e.End, opts.Now); err != nil {
+			entry.Reason, entry.Error = ReasonInvalid, err.Error()
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		description := strings.TrimSpace(e.Summary)
+		if description == "" {
+			description = e.UID
+		}
+
+		entry.TaskId, err = store.CreateTimeEntry(ctx, post.NewTask{
+			UserId:      opts.UserId,
+			Description: description,
+			ProjectId:   opts.ProjectId,
+			Billable:    opts.Billable,
+			ExternalUid: &e.UID,
+		}, e.Start, e.End, opts.AllowOverlap)
+
+		switch {
+		case errors.Is(err, storage.ErrAlreadyImported):
+			entry.Reason = ReasonAlreadyImported
+			report.Skipped = append(report.Skipped, entry)
+		case errors.Is(err, storage.ErrOverlap):
+			entry.Reason = ReasonOverlap
+			report.Conflicting = append(report.Conflicting, entry)
+		case errors.Is(err, storage.ErrPeriodLocked):
+			entry.Reason = ReasonPeriodLocked
+			report.Conflicting = append(report.Conflicting, entry)
+		case err != nil:
+			return report, err
+		default:
+			report.Created = append(report.Created, entry)
+		}
+	}
+
+	return report, nil
+}
+
+func skipReason(e ical.Event, opts Options) string {
+	switch {
+	case e.Err != nil:
+		return ReasonInvalid
+	case e.Cancelled:
+		return ReasonCancelled
+	case e.Recurring:
+		return ReasonRecurring
+	case e.AllDay:
+		return ReasonAllDay
+	case !matches(e, opts):
+		return ReasonFiltered
+	}
+
+	return ""
+}
+
+func matches(e ical.Event, opts Options) bool {
+	if opts.Category != "" {
+		found := false
+		for _, c := range e.Categories {
+			if strings.EqualFold(c, opts.Category) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if opts.Keyword != "" {
+		keyword := strings.ToLower(opts.Keyword)
+		if !strings.Contains(strings.ToLower(e.Summary), keyword) &&
+			!strings.Contains(strings.ToLower(e.Description), keyword) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package format
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	JSON = "json"
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+var ErrUnsupported = errors.New("unsupported response format")
+
+var mediaTypes = map[string]string{
+	"application/json": JSON,
+	"text/csv":         CSV,
+
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": XLSX,
+}
+
+// Negotiate picks the response format from the format query parameter or, without
+// it, from the Accept header. JSON is used when neither asks for a known format.
+func Negotiate(r *http.Request) (string, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		for _, known := range mediaTypes {
+			if f == known {
+				return f, nil
+			}
+		}
+
+		return "", ErrUnsupported
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		if f, ok := mediaTypes[mediaType]; ok {
+			return f, nil
+		}
+	}
+
+	return JSON, nil
+}
@@ -0,0 +1,25 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const StatusError = "Error"
+
+type Response struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Error writes a JSON error with a machine-readable reason.
+func Error(w http.ResponseWriter, r *http.Request, status int, msg, reason string) {
+	render.Status(r, status)
+	render.JSON(w, r, Response{
+		Status: StatusError,
+		Error:  msg,
+		Reason: reason,
+	})
+}
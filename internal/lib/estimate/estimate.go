@@ -0,0 +1,20 @@
+package estimate
+
+import (
+	"log/slog"
+
+	"time_tracker/internal/storage/post"
+)
+
+// Log emits an event for every task whose tracked time crossed an estimate threshold.
+func Log(log *slog.Logger, alerts []post.EstimateAlert) {
+	for _, a := range alerts {
+		log.Warn("task estimate threshold reached",
+			slog.Int("task_id", a.TaskId),
+			slog.Int("user_id", a.UserId),
+			slog.Int("threshold", a.Threshold),
+			slog.Int("estimate_minutes", a.EstimateMinutes),
+			slog.Float64("tracked_minutes", a.TrackedSeconds/60),
+		)
+	}
+}
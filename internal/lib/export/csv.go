@@ -0,0 +1,298 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"time_tracker/internal/lib/report"
+	"time_tracker/internal/storage/post"
+)
+
+var ErrInvalidDelimiter = errors.New("csv delimiter must be a single character or tab")
+
+// CSV writes reports as RFC 4180 CSV with a header row.
+type CSV struct {
+	Delimiter rune
+	// Timeout replaces the write timeout of the server for the export, large exports
+	// take longer to send than ordinary responses.
+	Timeout time.Duration
+}
+
+// Delimiter parses a delimiter given as a single character or as "tab".
+func Delimiter(s string) (rune, error) {
+	if s == "tab" || s == `\t` {
+		return '\t', nil
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == '"' || r == '\r' || r == '\n' {
+		return 0, ErrInvalidDelimiter
+	}
+
+	return r, nil
+}
+
+// ForRequest applies the delimiter query parameter of the request, if any.
+func (c CSV) ForRequest(r *http.Request) (CSV, error) {
+	if d := r.URL.Query().Get("delimiter"); d != "" {
+		delimiter, err := Delimiter(d)
+		if err != nil {
+			return c, err
+		}
+
+		c.Delimiter = delimiter
+	}
+
+	return c, nil
+}
+
+// Start sends the CSV headers of an attachment named filename and returns a writer
+// of the rows. Rows are written straight to the response, so they are not kept in memory.
+// The write deadline is moved to Timeout from now, the write timeout of the server
+// still applies if the response writer does not support it.
+func (c CSV) Start(w http.ResponseWriter, filename string) *csv.Writer {
+	if c.Timeout > 0 {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(c.Timeout))
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+
+	cw := csv.NewWriter(w)
+	cw.Comma = c.Delimiter
+	cw.UseCRLF = true
+
+	return cw
+}
+
+// text escapes a cell that a spreadsheet would read as a formula, with a leading quote.
+func text(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+var TaskRowHeader = []string{
+	"id", "user_id", "description", "status", "start_time", "end_time",
+	"duration_seconds", "estimate_minutes",
+}
+
+func TaskRowRecord(t post.TaskRow) []string {
+	return []string{
+		strconv.Itoa(t.Id),
+		strconv.Itoa(t.UserId),
+		text(t.Description),
+		string(t.Status),
+		formatTime(t.StartTime),
+		formatTime(t.EndTime),
+		formatFloat(t.DurationSeconds),
+		formatInt(t.EstimateMinutes),
+	}
+}
+
+// WriteTaskTimes writes the per task report of a user.
+func WriteTaskTimes(cw *csv.Writer, times []post.TaskTime) error {
+	cw.Write([]string{
+		"task_id", "description", "project_id", "parent_id", "tags",
+		"seconds", "hours", "minutes", "total_seconds", "billable", "amount",
+	})
+
+	for _, t := range times {
+		if err := cw.Write([]string{
+			strconv.Itoa(t.TaskID),
+			text(t.Description),
+			formatInt(t.ProjectId),
+			formatInt(t.ParentId),
+			text(strings.Join(t.Tags, ",")),
+			formatFloat(t.Seconds),
+			formatFloat(t.Hours),
+			formatFloat(t.Minutes),
+			formatFloat(t.TotalSeconds),
+			strconv.FormatBool(t.Billable),
+			t.Amount.StringFixed(2),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return flush(cw)
+}
+
+// WriteGroups writes the totals per project or per tag of a per task report.
+func WriteGroups(cw *csv.Writer, groupBy string, groups []report.Group) error {
+	cw.Write([]string{groupBy, "seconds", "hours", "minutes"})
+
+	for _, g := range groups {
+		key := g.Tag
+		if groupBy == report.GroupByProject {
+			key = formatInt(g.ProjectId)
+		}
+
+		if err := cw.Write([]string{
+			text(key),
+			formatFloat(g.Seconds),
+			formatFloat(g.Hours),
+			formatFloat(g.Minutes),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return flush(cw)
+}
+
+// WriteProjectTimes writes the time of all users per project.
+func WriteProjectTimes(cw *csv.Writer, projects []post.ProjectTime) error {
+	cw.Write([]string{"project_id", "name", "users", "seconds", "hours", "minutes"})
+
+	for _, p := range projects {
+		if err := cw.Write([]string{
+			strconv.Itoa(p.ProjectId),
+			text(p.Name),
+			strconv.Itoa(p.Users),
+			formatFloat(p.Seconds),
+			formatFloat(p.Hours),
+			formatFloat(p.Minutes),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return flush(cw)
+}
+
+// WriteSummary writes a row per description and project of every bucket,
+// empty buckets get a row without a description. The time credited for absences
+// is of the bucket, so it is written in its first row only and the column sums up.
+func WriteSummary(cw *csv.Writer, buckets []report.Bucket) error {
+	cw.Write([]string{
+		"period", "start", "end", "description", "project_id", "seconds", "hours", "minutes",
+		"credited_seconds",
+	})
+
+	for _, b := range buckets {
+		start, end := b.Start.Format(time.RFC3339), b.End.Format(time.RFC3339)
+		credited := formatFloat(b.CreditedSeconds)
+
+		if len(b.Items) == 0 {
+			if err := cw.Write([]string{b.Key, start, end, "", "", "0", "0", "0", credited}); err != nil {
+				return err
+			}
+		}
+
+		for i, item := range b.Items {
+			if i > 0 {
+				credited = ""
+			}
+
+			if err := cw.Write([]string{
+				b.Key,
+				start,
+				end,
+				text(item.Description),
+				formatInt(item.ProjectId),
+				formatFloat(item.Seconds),
+				formatFloat(item.Hours),
+				formatFloat(item.Minutes),
+				credited,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush(cw)
+}
+
+// WriteOvertime writes a row per day and a total row of every period without a date,
+// since overtime of one day of the period makes up for undertime of another.
+func WriteOvertime(cw *csv.Writer, periods []report.OvertimePeriod) error {
+	cw.Write([]string{
+		"period", "date", "expected_hours", "actual_hours", "credited_hours",
+		"overtime_hours", "undertime_hours",
+	})
+
+	row := func(period, date string, wt report.WorkTime) error {
+		return cw.Write([]string{
+			period,
+			date,
+			formatFloat(wt.Expected),
+			formatFloat(wt.Actual),
+			formatFloat(wt.Credited),
+			formatFloat(wt.Overtime),
+			formatFloat(wt.Undertime),
+		})
+	}
+
+	for _, p := range periods {
+		for _, d := range p.Days {
+			if err := row(p.Key, d.Date, d.WorkTime); err != nil {
+				return err
+			}
+		}
+
+		if err := row(p.Key, "", p.WorkTime); err != nil {
+			return err
+		}
+	}
+
+	return flush(cw)
+}
+
+// WriteTeam writes a row per user and day with the time credited for absences apart.
+func WriteTeam(cw *csv.Writer, team report.Team) error {
+	cw.Write([]string{"user_id", "surname", "name", "day", "seconds", "hours", "minutes", "credited_seconds"})
+
+	for _, u := range team.Users {
+		for _, d := range u.Days {
+			if err := cw.Write([]string{
+				strconv.Itoa(u.UserId),
+				text(u.Surname),
+				text(u.Name),
+				d.Day,
+				formatFloat(d.Seconds),
+				formatFloat(d.Hours),
+				formatFloat(d.Minutes),
+				formatFloat(d.CreditedSeconds),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush(cw)
+}
+
+// flush sends the rows left in the buffer.
+func flush(cw *csv.Writer) error {
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*i)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"time_tracker/internal/lib/report"
+)
+
+const pdfFont = "timesheet"
+
+// ErrNoPDFFont is returned for timesheets without a font, the core fonts of PDF have
+// no Cyrillic letters and would garble Russian names and tasks.
+var ErrNoPDFFont = errors.New("pdf font is not configured")
+
+// widths of the day, task, hours and day total columns of the timesheet table in mm.
+var pdfColumns = [4]float64{25, 110, 22, 23}
+
+// PDF renders printable timesheets.
+type PDF struct {
+	// font is a TrueType font with Cyrillic letters, timesheets are refused without it.
+	font []byte
+}
+
+// NewPDF reads the TrueType font at fontPath, timesheets are disabled if it is empty.
+func NewPDF(fontPath string) (PDF, error) {
+	if fontPath == "" {
+		return PDF{}, nil
+	}
+
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		return PDF{}, fmt.Errorf("unable to read pdf font: %w", err)
+	}
+
+	return PDF{font: font}, nil
+}
+
+// Enabled reports whether a font is loaded, so timesheets can be rendered.
+func (p PDF) Enabled() bool {
+	return p.font != nil
+}
+
+// PDFTimesheet is a monthly timesheet of a user to be signed.
+type PDFTimesheet struct {
+	// FullName is surname, name and patronymic of the user.
+	FullName string
+	Month    time.Time
+	Rows     []report.Bucket
+}
+
+// Write sends the timesheet as an A4 document: a header with the user and the month,
+// a row per task and day, a row of the time credited for absences on a day, day and
+// month totals and signature lines. The document is
+// built before anything is sent, so a failure can still be reported with an error status.
+func (p PDF) Write(w http.ResponseWriter, filename string, ts PDFTimesheet) error {
+	if !p.Enabled() {
+		return ErrNoPDFFont
+	}
+
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.SetMargins(15, 15, 15)
+	doc.SetAutoPageBreak(true, 15)
+	doc.AliasNbPages("")
+
+	doc.AddUTF8FontFromBytes(pdfFont, "", p.font)
+
+	doc.SetFooterFunc(func() {
+		doc.SetY(-12)
+		doc.SetFont(pdfFont, "", 8)
+		doc.CellFormat(0, 5, fmt.Sprintf("%d / {nb}", doc.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	doc.AddPage()
+
+	doc.SetFont(pdfFont, "", 14)
+	doc.CellFormat(0, 8, "Timesheet for "+ts.Month.Format("January 2006"), "", 1, "C", false, 0, "")
+
+	doc.SetFont(pdfFont, "", 10)
+	doc.CellFormat(0, 6, "Employee: "+ts.FullName, "", 1, "L", false, 0, "")
+	doc.Ln(4)
+
+	header := func() {
+		doc.SetFillColor(230, 230, 230)
+		for i, title := range []string{"Day", "Task", "Hours", "Day total"} {
+			doc.CellFormat(pdfColumns[i], 7, title, "1", 0, "C", true, 0, "")
+		}
+		doc.Ln(-1)
+	}
+
+	header()
+
+	var total, credited float64
+	for _, day := range ts.Rows {
+		items := day.Items
+		if day.CreditedSeconds > 0 {
+			items = append(items[:len(items):len(items)], report.SummaryItem{
+				Description: "Absence (credited)",
+				Seconds:     day.CreditedSeconds,
+			})
+		}
+
+		if len(items) == 0 {
+			items = []report.SummaryItem{{}}
+		}
+
+		for i, item := range items {
+			// the table header is repeated on every page
+			if doc.GetY()+6 > pageBottom(doc) {
+				doc.AddPage()
+				header()
+			}
+
+			var dayCell, dayTotal, hours string
+			if i == 0 {
+				dayCell = day.Start.Format("02.01 Mon")
+				dayTotal = formatHours(day.Seconds + day.CreditedSeconds)
+			}
+
+			task := item.Description
+			if item.ProjectId != nil {
+				task += " (project " + strconv.Itoa(*item.ProjectId) + ")"
+			}
+
+			if item.Description != "" {
+				hours = formatHours(item.Seconds)
+			}
+
+			doc.CellFormat(pdfColumns[0], 6, dayCell, "1", 0, "L", false, 0, "")
+			doc.CellFormat(pdfColumns[1], 6, fit(doc, task, pdfColumns[1]-2), "1", 0, "L", false, 0, "")
+			doc.CellFormat(pdfColumns[2], 6, hours, "1", 0, "R", false, 0, "")
+			doc.CellFormat(pdfColumns[3], 6, dayTotal, "1", 1, "R", false, 0, "")
+		}
+
+		total += day.Seconds
+		credited += day.CreditedSeconds
+	}
+
+	if credited > 0 {
+		doc.CellFormat(pdfColumns[0]+pdfColumns[1]+pdfColumns[2], 7, "Worked", "1", 0, "R", true, 0, "")
+		doc.CellFormat(pdfColumns[3], 7, formatHours(total), "1", 1, "R", true, 0, "")
+		doc.CellFormat(pdfColumns[0]+pdfColumns[1]+pdfColumns[2], 7, "Credited for absences", "1", 0, "R", true, 0, "")
+		doc.CellFormat(pdfColumns[3], 7, formatHours(credited), "1", 1, "R", true, 0, "")
+	}
+
+	doc.CellFormat(pdfColumns[0]+pdfColumns[1]+pdfColumns[2], 7, "Total", "1", 0, "R", true, 0, "")
+	doc.CellFormat(pdfColumns[3], 7, formatHours(total+credited), "1", 1, "R", true, 0, "")
+
+	// the signature lines are not split from each other by a page break
+	if doc.GetY()+40 > pageBottom(doc) {
+		doc.AddPage()
+	}
+
+	doc.Ln(15)
+	signature(doc, "Employee", initials(ts.FullName))
+	doc.Ln(10)
+	signature(doc, "Manager", "")
+
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		return fmt.Errorf("unable to render pdf: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// signature writes lines for the signature, the name and the date of a role. The name
+// is printed on its line, the other lines are filled in by hand.
+func signature(doc *fpdf.Fpdf, role, name string) {
+	doc.CellFormat(25, 6, role, "", 0, "L", false, 0, "")
+	doc.CellFormat(50, 6, "", "B", 0, "L", false, 0, "")
+	doc.CellFormat(5, 6, "", "", 0, "L", false, 0, "")
+	doc.CellFormat(50, 6, name, "B", 0, "C", false, 0, "")
+	doc.CellFormat(5, 6, "", "", 0, "L", false, 0, "")
+	doc.CellFormat(35, 6, "", "B", 1, "L", false, 0, "")
+
+	doc.SetFontSize(7)
+	doc.CellFormat(25, 4, "", "", 0, "L", false, 0, "")
+	doc.CellFormat(50, 4, "signature", "", 0, "C", false, 0, "")
+	doc.CellFormat(5, 4, "", "", 0, "L", false, 0, "")
+	doc.CellFormat(50, 4, "name", "", 0, "C", false, 0, "")
+	doc.CellFormat(5, 4, "", "", 0, "L", false, 0, "")
+	doc.CellFormat(35, 4, "date", "", 1, "C", false, 0, "")
+	doc.SetFontSize(10)
+}
+
+func pageBottom(doc *fpdf.Fpdf) float64 {
+	_, height := doc.GetPageSize()
+	_, _, _, bottom := doc.GetMargins()
+	return height - bottom
+}
+
+// fit cuts s to width mm, marking the cut with dots.
+func fit(doc *fpdf.Fpdf, s string, width float64) string {
+	if doc.GetStringWidth(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && doc.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "..."
+}
+
+// initials shortens "Surname Name Patronymic" to "Surname N. P.".
+func initials(fullName string) string {
+	parts := strings.Fields(fullName)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	short := parts[0]
+	for _, part := range parts[1:] {
+		short += " " + string([]rune(part)[0]) + "."
+	}
+
+	return short
+}
+
+func formatHours(seconds float64) string {
+	return strconv.FormatFloat(seconds/3600, 'f', 2, 64)
+}
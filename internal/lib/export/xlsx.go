@@ -0,0 +1,211 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"time_tracker/internal/lib/report"
+)
+
+const summarySheet = "Summary"
+
+// hoursFormat is the built-in number format "0.00".
+const hoursFormat = 2
+
+// WriteXLSX sends a workbook with a summary sheet and a sheet per timesheet: a row per
+// day, a column per task, a column of the time credited for absences if there is any
+// and totals computed by formulas. The workbook is built before
+// anything is sent, so a failure can still be reported with an error status.
+func WriteXLSX(w http.ResponseWriter, filename string, sheets []report.Timesheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(f.GetSheetName(0), summarySheet); err != nil {
+		return err
+	}
+
+	style, err := f.NewStyle(&excelize.Style{NumFmt: hoursFormat})
+	if err != nil {
+		return err
+	}
+
+	if err := setRow(f, summarySheet, 1, "User", "Hours"); err != nil {
+		return err
+	}
+
+	for i, ts := range sheets {
+		name := sheetName(ts)
+
+		if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+
+		total, err := writeTimesheet(f, name, ts, style)
+		if err != nil {
+			return err
+		}
+
+		row := i + 2
+		if err := f.SetCellValue(summarySheet, cell(1, row), ts.Name); err != nil {
+			return err
+		}
+
+		if err := f.SetCellFormula(summarySheet, cell(2, row), fmt.Sprintf("'%s'!%s", name, total)); err != nil {
+			return err
+		}
+	}
+
+	last := len(sheets) + 2
+	if err := f.SetCellValue(summarySheet, cell(1, last), "Total"); err != nil {
+		return err
+	}
+
+	if err := f.SetCellFormula(summarySheet, cell(2, last), sumFormula(2, 2, 2, last-1)); err != nil {
+		return err
+	}
+
+	if err := f.SetCellStyle(summarySheet, cell(2, 2), cell(2, last), style); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.xlsx"`)
+
+	return f.Write(w)
+}
+
+// writeTimesheet fills a sheet and returns the cell of its grand total.
+func writeTimesheet(f *excelize.File, sheet string, ts report.Timesheet, style int) (string, error) {
+	columns := make(map[string]int)
+	header := []any{"Day"}
+
+	for _, row := range ts.Rows {
+		for _, item := range row.Items {
+			key := itemKey(item)
+			if _, ok := columns[key]; ok {
+				continue
+			}
+
+			title := item.Description
+			if item.ProjectId != nil {
+				title += " (project " + strconv.Itoa(*item.ProjectId) + ")"
+			}
+
+			columns[key] = len(header) + 1
+			header = append(header, title)
+		}
+	}
+
+	totalCol := len(header) + 1
+	header = append(header, "Total")
+
+	// time credited for absences is apart from the tracked total
+	creditedCol := 0
+	for _, row := range ts.Rows {
+		if row.CreditedSeconds > 0 {
+			creditedCol = len(header) + 1
+			header = append(header, "Credited")
+			break
+		}
+	}
+
+	if err := setRow(f, sheet, 1, header...); err != nil {
+		return "", err
+	}
+
+	for i, row := range ts.Rows {
+		r := i + 2
+
+		if err := f.SetCellValue(sheet, cell(1, r), row.Key); err != nil {
+			return "", err
+		}
+
+		for _, item := range row.Items {
+			if err := f.SetCellValue(sheet, cell(columns[itemKey(item)], r), item.Seconds/3600); err != nil {
+				return "", err
+			}
+		}
+
+		if err := f.SetCellFormula(sheet, cell(totalCol, r), sumFormula(2, r, totalCol-1, r)); err != nil {
+			return "", err
+		}
+
+		if creditedCol > 0 {
+			if err := f.SetCellValue(sheet, cell(creditedCol, r), row.CreditedSeconds/3600); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	lastCol := totalCol
+	if creditedCol > 0 {
+		lastCol = creditedCol
+	}
+
+	last := len(ts.Rows) + 2
+	if err := f.SetCellValue(sheet, cell(1, last), "Total"); err != nil {
+		return "", err
+	}
+
+	for col := 2; col <= lastCol; col++ {
+		if err := f.SetCellFormula(sheet, cell(col, last), sumFormula(col, 2, col, last-1)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := f.SetCellStyle(sheet, cell(2, 2), cell(lastCol, last), style); err != nil {
+		return "", err
+	}
+
+	return cell(totalCol, last), nil
+}
+
+func setRow(f *excelize.File, sheet string, row int, values ...any) error {
+	return f.SetSheetRow(sheet, cell(1, row), &values)
+}
+
+func cell(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
+
+func sumFormula(fromCol, fromRow, toCol, toRow int) string {
+	if toCol < fromCol || toRow < fromRow {
+		return "0"
+	}
+
+	return "SUM(" + cell(fromCol, fromRow) + ":" + cell(toCol, toRow) + ")"
+}
+
+func itemKey(item report.SummaryItem) string {
+	key := item.Description + "\x00"
+	if item.ProjectId != nil {
+		key += strconv.Itoa(*item.ProjectId)
+	}
+
+	return key
+}
+
+// sheetName makes a sheet name of at most 31 characters without the characters
+// Excel does not allow. The user id keeps the names unique.
+func sheetName(ts report.Timesheet) string {
+	name := strconv.Itoa(ts.UserId)
+	if ts.Name != "" {
+		name += " " + strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\'`, r) {
+				return '_'
+			}
+			return r
+		}, ts.Name)
+	}
+
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	return name
+}
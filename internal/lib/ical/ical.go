@@ -0,0 +1,106 @@
+// Package ical writes iCalendar (RFC 5545) calendars of time entries.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar data.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLine is the longest content line in octets, without the line break.
+const maxLine = 75
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Event is a VEVENT of the calendar.
+type Event struct {
+	// UID must not change when the event is sent again, so calendar apps update
+	// the event instead of adding a copy.
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+
+	// Categories, AllDay, Recurring, Cancelled and Err are set by Parse only.
+	Categories []string
+	// AllDay is set for an event of whole days without times.
+	AllDay bool
+	// Recurring is set for an event with a recurrence rule or for an instance of one.
+	Recurring bool
+	Cancelled bool
+	// Err tells why the times or the UID of the event could not be read.
+	Err error
+}
+
+// Write writes a calendar named name with the events. now is the DTSTAMP of the events.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	b := bufio.NewWriter(w)
+
+	line(b, "BEGIN:VCALENDAR")
+	line(b, "VERSION:2.0")
+	line(b, "PRODID:-//time_tracker//time entries//EN")
+	line(b, "CALSCALE:GREGORIAN")
+	line(b, "METHOD:PUBLISH")
+	line(b, "X-WR-CALNAME:"+Escape(name))
+
+	stamp := now.UTC().Format(dateTimeLayout)
+
+	for _, e := range events {
+		line(b, "BEGIN:VEVENT")
+		line(b, "UID:"+e.UID)
+		line(b, "DTSTAMP:"+stamp)
+		line(b, "DTSTART:"+e.Start.UTC().Format(dateTimeLayout))
+		line(b, "DTEND:"+e.End.UTC().Format(dateTimeLayout))
+		line(b, "SUMMARY:"+Escape(e.Summary))
+		if e.Description != "" {
+			line(b, "DESCRIPTION:"+Escape(e.Description))
+		}
+		line(b, "TRANSP:TRANSPARENT")
+		line(b, "END:VEVENT")
+	}
+
+	line(b, "END:VCALENDAR")
+
+	return b.Flush()
+}
+
+// Escape escapes a TEXT value: backslashes, semicolons, commas and line breaks.
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// line writes a content line ended by CRLF, folded into lines of at most 75 octets.
+// Continuation lines start with a space, which counts towards their length, and a line
+// is never split inside a UTF-8 sequence. Errors are reported by the Flush of the writer.
+func line(b *bufio.Writer, s string) {
+	limit := maxLine
+
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+
+		s = s[cut:]
+		limit = maxLine - 1
+	}
+
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
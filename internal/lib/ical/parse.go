@@ -0,0 +1,380 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotCalendar = errors.New("data is not an iCalendar")
+	ErrNoUID       = errors.New("event has no UID")
+	ErrNoStart     = errors.New("event has no DTSTART")
+	// ErrUnknownTimeZone is a TZID that is neither an IANA nor a Windows time zone.
+	ErrUnknownTimeZone = errors.New("unknown time zone")
+)
+
+// property is a content line: NAME;PARAM=value:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of a calendar. Floating times, without a time zone, are read
+// in loc. An event that can not be read, also one in an unknown time zone, is returned
+// with Err set, so the other events can still be used.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrNotCalendar
+	}
+
+	var (
+		events []Event
+		// components are the names of the components the line is in, VALARMs are
+		// nested in VEVENTs
+		components []string
+		props      []property
+	)
+
+	for _, l := range lines {
+		p, err := parseLine(l)
+		if err != nil {
+			return nil, err
+		}
+
+		switch p.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(p.value))
+			if strings.EqualFold(p.value, "VEVENT") {
+				props = nil
+			}
+			continue
+		case "END":
+			if len(components) == 0 {
+				return nil, fmt.Errorf("%w: END:%s without BEGIN", ErrNotCalendar, p.value)
+			}
+
+			components = components[:len(components)-1]
+			if strings.EqualFold(p.value, "VEVENT") {
+				events = append(events, event(props, loc))
+			}
+			continue
+		}
+
+		if len(components) > 0 && components[len(components)-1] == "VEVENT" {
+			props = append(props, p)
+		}
+	}
+
+	return events, nil
+}
+
+func event(props []property, loc *time.Location) Event {
+	var (
+		e        Event
+		end      *property
+		duration string
+		hasStart bool
+	)
+
+	for i, p := range props {
+		switch p.name {
+		case "UID":
+			e.UID = p.value
+		case "SUMMARY":
+			e.Summary = unescape(p.value)
+		case "DESCRIPTION":
+			e.Description = unescape(p.value)
+		case "CATEGORIES":
+			for _, c := range splitList(p.value) {
+				if c = strings.TrimSpace(unescape(c)); c != "" {
+					e.Categories = append(e.Categories, c)
+				}
+			}
+		case "STATUS":
+			e.Cancelled = strings.EqualFold(p.value, "CANCELLED")
+		case "RRULE", "RDATE", "RECURRENCE-ID":
+			e.Recurring = true
+		case "DTSTART":
+			hasStart = true
+
+			var err error
+			e.Start, e.AllDay, err = parseTime(p, loc)
+			if err != nil && e.Err == nil {
+				e.Err = fmt.Errorf("invalid DTSTART: %w", err)
+			}
+		case "DTEND":
+			end = &props[i]
+		case "DURATION":
+			duration = p.value
+		}
+	}
+
+	switch {
+	case e.Err != nil:
+	case e.UID == "":
+		e.Err = ErrNoUID
+	case !hasStart:
+		e.Err = ErrNoStart
+	case end != nil:
+		var err error
+		if e.End, _, err = parseTime(*end, loc); err != nil {
+			e.Err = fmt.Errorf("invalid DTEND: %w", err)
+		}
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			e.Err = fmt.Errorf("invalid DURATION: %w", err)
+			break
+		}
+
+		e.End = d.add(e.Start)
+	case e.AllDay:
+		// an all-day event without an end lasts one day
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+
+	return e
+}
+
+// unfold joins folded lines: a line starting with a space or a tab continues
+// the previous one.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		l := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseLine splits a content line into its name, parameters and value. The value
+// starts at the first colon that is not inside a quoted parameter value.
+func parseLine(l string) (property, error) {
+	quoted := false
+	colon := -1
+
+	for i, c := range l {
+		if c == '"' {
+			quoted = !quoted
+		}
+
+		if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+
+	if colon < 0 {
+		return property{}, fmt.Errorf("%w: invalid line %q", ErrNotCalendar, l)
+	}
+
+	parts := splitOutsideQuotes(l[:colon], ';')
+	p := property{
+		name:  strings.ToUpper(parts[0]),
+		value: l[colon+1:],
+	}
+
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		if p.params == nil {
+			p.params = make(map[string]string)
+		}
+		p.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return p, nil
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// splitList splits a list of TEXT values on the commas that are not escaped.
+func splitList(s string) []string {
+	var (
+		parts []string
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescape reverts Escape.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// parseTime reads a DATE-TIME in UTC, in the TZID zone or floating, or a DATE,
+// which starts an all-day event. The definitions of VTIMEZONE are not read, a TZID
+// must name an IANA or a Windows time zone.
+func parseTime(p property, loc *time.Location) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", p.value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse(dateTimeLayout, p.value)
+		return t, false, err
+	}
+
+	if tzid := p.params["TZID"]; tzid != "" {
+		zone, err := timeZone(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		loc = zone
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", p.value, loc)
+	return t, false, err
+}
+
+// duration is a DURATION value. Days and weeks are nominal, so they are kept apart
+// from the exact time: a day across a DST change is 23 or 25 hours long.
+type duration struct {
+	days int
+	time time.Duration
+}
+
+func (d duration) add(t time.Time) time.Time {
+	return t.AddDate(0, 0, d.days).Add(d.time)
+}
+
+// parseDuration reads a duration like P1W, P1DT2H30M or PT15M.
+func parseDuration(s string) (duration, error) {
+	var d duration
+
+	sign := 1
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	inTime := false
+	number := ""
+
+	for _, c := range s[1:] {
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+
+		if c == 'T' {
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+		number = ""
+
+		switch {
+		case c == 'W' && !inTime:
+			d.days += 7 * n
+		case c == 'D' && !inTime:
+			d.days += n
+		case c == 'H' && inTime:
+			d.time += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d.time += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d.time += time.Duration(n) * time.Second
+		default:
+			return duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+
+	if number != "" {
+		return duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	d.days *= sign
+	d.time *= time.Duration(sign)
+
+	return d, nil
+}
@@ -0,0 +1,328 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func calendar(lines ...string) string {
+	return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...), "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	moscow := mustLoad(t, "Europe/Moscow")
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name string
+		data string
+		want []Event
+	}{
+		{
+			name: "folded lines",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:fold",
+				"DTSTART:20240305T090000Z",
+				"DTEND:20240305T100000Z",
+				"SUMMARY:Long ",
+				" summary split",
+				"\tover three lines",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:     "fold",
+				Start:   time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+				End:     time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				Summary: "Long summary splitover three lines",
+			}},
+		},
+		{
+			name: "escaped commas in categories",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:cat",
+				"DTSTART:20240305T090000Z",
+				"DTEND:20240305T100000Z",
+				`CATEGORIES:work,research\, design, ,meetings`,
+				`SUMMARY:a\, b\; c\nd`,
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:        "cat",
+				Start:      time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+				End:        time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				Summary:    "a, b; c\nd",
+				Categories: []string{"work", "research, design", "meetings"},
+			}},
+		},
+		{
+			name: "date is an all-day event in loc",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:date",
+				"DTSTART;VALUE=DATE:20240305",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:    "date",
+				Start:  time.Date(2024, 3, 5, 0, 0, 0, 0, moscow),
+				End:    time.Date(2024, 3, 6, 0, 0, 0, 0, moscow),
+				AllDay: true,
+			}},
+		},
+		{
+			name: "floating date-time is in loc",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:floating",
+				"DTSTART:20240305T090000",
+				"DTEND:20240305T100000",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:   "floating",
+				Start: time.Date(2024, 3, 5, 9, 0, 0, 0, moscow),
+				End:   time.Date(2024, 3, 5, 10, 0, 0, 0, moscow),
+			}},
+		},
+		{
+			name: "quoted tzid and windows tzid",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:tzid",
+				`DTSTART;TZID="Europe/Berlin":20240305T090000`,
+				`DTEND;TZID="Russian Standard Time":20240305T120000`,
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:   "tzid",
+				Start: time.Date(2024, 3, 5, 9, 0, 0, 0, berlin),
+				End:   time.Date(2024, 3, 5, 12, 0, 0, 0, moscow),
+			}},
+		},
+		{
+			name: "negative duration",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:negative",
+				"DTSTART:20240305T090000Z",
+				"DURATION:-P1DT2H",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:   "negative",
+				Start: time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "alarms, recurrence and cancellation",
+			data: calendar(
+				"BEGIN:VEVENT",
+				"UID:flags",
+				"DTSTART:20240305T090000Z",
+				"DTEND:20240305T100000Z",
+				"RRULE:FREQ=WEEKLY",
+				"STATUS:CANCELLED",
+				"BEGIN:VALARM",
+				"DESCRIPTION:alarm",
+				"END:VALARM",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:       "flags",
+				Start:     time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+				End:       time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				Recurring: true,
+				Cancelled: true,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.data), moscow)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEventErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  error
+	}{
+		{
+			name:  "no uid",
+			lines: []string{"DTSTART:20240305T090000Z"},
+			want:  ErrNoUID,
+		},
+		{
+			name:  "no start",
+			lines: []string{"UID:x"},
+			want:  ErrNoStart,
+		},
+		{
+			name:  "invalid start",
+			lines: []string{"UID:x", "DTSTART:2024-03-05"},
+		},
+		{
+			name:  "invalid end",
+			lines: []string{"UID:x", "DTSTART:20240305T090000Z", "DTEND:tomorrow"},
+		},
+		{
+			name:  "unknown tzid",
+			lines: []string{"UID:x", "DTSTART;TZID=Mars/Olympus:20240305T090000"},
+			want:  ErrUnknownTimeZone,
+		},
+		{
+			name:  "local tzid",
+			lines: []string{"UID:x", "DTSTART:20240305T090000Z", "DTEND;TZID=Local:20240305T100000"},
+			want:  ErrUnknownTimeZone,
+		},
+		{
+			name:  "invalid duration",
+			lines: []string{"UID:x", "DTSTART:20240305T090000Z", "DURATION:P1H"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append(append([]string{"BEGIN:VEVENT"}, tt.lines...), "END:VEVENT")
+
+			events, err := Parse(strings.NewReader(calendar(lines...)), time.UTC)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if len(events) != 1 || events[0].Err == nil {
+				t.Fatalf("Parse() = %+v, want an event with Err", events)
+			}
+
+			if tt.want != nil && !errors.Is(events[0].Err, tt.want) {
+				t.Errorf("Err = %v, want %v", events[0].Err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "not a calendar", data: "BEGIN:VCARD\r\nEND:VCARD\r\n"},
+		{name: "line without colon", data: calendar("BEGIN:VEVENT", "UID", "END:VEVENT")},
+		{name: "colon only in quotes", data: calendar(`DTSTART;TZID="a:b"`)},
+		{name: "end without begin", data: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nEND:VEVENT\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.data), time.UTC); !errors.Is(err, ErrNotCalendar) {
+				t.Errorf("Parse() error = %v, want %v", err, ErrNotCalendar)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	// the day before the switch to summer time in Berlin
+	start := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "PT15M", want: start.Add(15 * time.Minute)},
+		{value: "+PT1H30M10S", want: start.Add(time.Hour + 30*time.Minute + 10*time.Second)},
+		// a nominal day across the DST change is 23 hours long
+		{value: "P1D", want: time.Date(2024, 3, 31, 12, 0, 0, 0, berlin)},
+		{value: "P1W", want: time.Date(2024, 4, 6, 12, 0, 0, 0, berlin)},
+		{value: "P1DT2H", want: time.Date(2024, 3, 31, 14, 0, 0, 0, berlin)},
+		{value: "-P1DT2H", want: time.Date(2024, 3, 29, 10, 0, 0, 0, berlin)},
+		{value: "P", wantErr: true},
+		{value: "1H", wantErr: true},
+		{value: "P1H", wantErr: true},
+		{value: "PT1D", wantErr: true},
+		{value: "PT1", wantErr: true},
+		{value: "PTH", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, err := parseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && !d.add(start).Equal(tt.want) {
+				t.Errorf("add() = %v, want %v", d.add(start), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	events := []Event{
+		{
+			UID:         "task-1@time_tracker",
+			Start:       time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+			End:         time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC),
+			Summary:     "Отчет, квартал; итоги",
+			Description: "first line\nsecond line with a backslash \\ " + strings.Repeat("долгий текст ", 20),
+		},
+		{
+			UID:     "task-2@time_tracker",
+			Start:   time.Date(2024, 3, 6, 23, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 3, 7, 1, 0, 0, 0, time.UTC),
+			Summary: "night",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "user, 1", events, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for _, l := range strings.Split(buf.String(), "\r\n") {
+		if len(l) > maxLine {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+
+	got, err := Parse(&buf, time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("Parse(Write()) = %+v, want %+v", got, events)
+	}
+}
@@ -0,0 +1,63 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// windowsZones maps the Windows time zone names, which Outlook and Exchange write as
+// TZID, to IANA zones, after the territory 001 entries of the CLDR windowsZones table.
+var windowsZones = map[string]string{
+	"UTC":                           "Etc/UTC",
+	"Kaliningrad Standard Time":     "Europe/Kaliningrad",
+	"Russian Standard Time":         "Europe/Moscow",
+	"Volgograd Standard Time":       "Europe/Volgograd",
+	"Astrakhan Standard Time":       "Europe/Astrakhan",
+	"Saratov Standard Time":         "Europe/Saratov",
+	"Russia Time Zone 3":            "Europe/Samara",
+	"Ekaterinburg Standard Time":    "Asia/Yekaterinburg",
+	"Omsk Standard Time":            "Asia/Omsk",
+	"N. Central Asia Standard Time": "Asia/Novosibirsk",
+	"Altai Standard Time":           "Asia/Barnaul",
+	"Tomsk Standard Time":           "Asia/Tomsk",
+	"North Asia Standard Time":      "Asia/Krasnoyarsk",
+	"North Asia East Standard Time": "Asia/Irkutsk",
+	"Yakutsk Standard Time":         "Asia/Yakutsk",
+	"Transbaikal Standard Time":     "Asia/Chita",
+	"Vladivostok Standard Time":     "Asia/Vladivostok",
+	"Sakhalin Standard Time":        "Asia/Sakhalin",
+	"Magadan Standard Time":         "Asia/Magadan",
+	"Russia Time Zone 10":           "Asia/Srednekolymsk",
+	"Russia Time Zone 11":           "Asia/Kamchatka",
+	"GMT Standard Time":             "Europe/London",
+	"W. Europe Standard Time":       "Europe/Berlin",
+	"Central Europe Standard Time":  "Europe/Budapest",
+	"Romance Standard Time":         "Europe/Paris",
+	"FLE Standard Time":             "Europe/Kiev",
+	"E. Europe Standard Time":       "Europe/Chisinau",
+	"Belarus Standard Time":         "Europe/Minsk",
+	"Turkey Standard Time":          "Europe/Istanbul",
+	"Eastern Standard Time":         "America/New_York",
+	"Central Standard Time":         "America/Chicago",
+	"Mountain Standard Time":        "America/Denver",
+	"Pacific Standard Time":         "America/Los_Angeles",
+}
+
+// timeZone loads the zone of a TZID given as an IANA or a Windows time zone name.
+func timeZone(tzid string) (*time.Location, error) {
+	if name, ok := windowsZones[tzid]; ok {
+		tzid = name
+	}
+
+	// LoadLocation takes "Local" for the zone of the server, which a calendar does not mean
+	if tzid == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTimeZone, tzid)
+	}
+
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTimeZone, tzid)
+	}
+
+	return loc, nil
+}
@@ -0,0 +1,64 @@
+package interval
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrEndBeforeStart = errors.New("end time must be after start time")
+	ErrInFuture       = errors.New("time entry must not be in the future")
+	ErrInvalidDate    = errors.New("date must be in YYYY-MM-DD format")
+	ErrInvalidMonth   = errors.New("month must be in YYYY-MM format")
+)
+
+// Validate checks a completed time entry from start to end against now.
+func Validate(start, end, now time.Time) error {
+	if !end.After(start) {
+		return ErrEndBeforeStart
+	}
+
+	if end.After(now) {
+		return ErrInFuture
+	}
+
+	return nil
+}
+
+// DateLayout is the format of calendar days in requests.
+const DateLayout = "2006-01-02"
+
+// Days returns the bounds of the calendar days from to to, both included, in loc:
+// the start of from and the start of the day after to. Days of a DST change
+// are 23 or 25 hours long.
+func Days(from, to string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DateLayout, from, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDate
+	}
+
+	last, err := time.ParseInLocation(DateLayout, to, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDate
+	}
+
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, ErrEndBeforeStart
+	}
+
+	return start, last.AddDate(0, 0, 1), nil
+}
+
+// MonthLayout is the format of calendar months in requests.
+const MonthLayout = "2006-01"
+
+// Month returns the bounds of the calendar month in loc: its first day and the
+// first day of the next month.
+func Month(month string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(MonthLayout, month, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidMonth
+	}
+
+	return start, start.AddDate(0, 1, 0), nil
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/lib/workcal"
+	"time_tracker/internal/storage/post"
+)
+
+// AbsenceCredit returns the hours credited for approved absences by YYYY-MM-DD day: the
+// working hours of the day by the calendar scaled by the part-time factor, half of them
+// for a half-day absence. Days off in an absence are not credited.
+func AbsenceCredit(absences []post.Absence, cal *workcal.Calendar, factor float64) map[string]float64 {
+	credit := make(map[string]float64)
+
+	for _, a := range absences {
+		for d := a.StartDate; d.Before(a.EndDate); d = d.AddDate(0, 0, 1) {
+			hours := cal.Hours(d) * factor
+			if a.HalfDay {
+				hours /= 2
+			}
+
+			if hours > 0 {
+				credit[d.Format(interval.DateLayout)] += hours
+			}
+		}
+	}
+
+	return credit
+}
+
+// Credit adds the credited hours of the days of every bucket to its CreditedSeconds.
+func Credit(buckets []Bucket, credit map[string]float64) {
+	if len(credit) == 0 {
+		return
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		for d := truncate(b.Start, PeriodDay); d.Before(b.End); d = d.AddDate(0, 0, 1) {
+			b.CreditedSeconds += credit[d.Format(interval.DateLayout)] * 3600
+		}
+	}
+}
+
+// TeamCredit returns the hours credited for the approved absences of a team by user
+// and YYYY-MM-DD day, with the part-time factor of every user.
+func TeamCredit(absences []post.TeamAbsence, cal *workcal.Calendar) map[int]map[string]float64 {
+	credit := make(map[int]map[string]float64)
+
+	for _, a := range absences {
+		user, ok := credit[a.UserId]
+		if !ok {
+			user = make(map[string]float64)
+			credit[a.UserId] = user
+		}
+
+		for day, hours := range AbsenceCredit([]post.Absence{a.Absence}, cal, a.PartTimeFactor) {
+			user[day] += hours
+		}
+	}
+
+	return credit
+}
+
+// CreditTeam adds the credited hours of every user to the CreditedSeconds of the user,
+// of the days of the user and of the team.
+func CreditTeam(team *Team, credit map[int]map[string]float64) {
+	if len(credit) == 0 {
+		return
+	}
+
+	dayIndex := make(map[string]int, len(team.Days))
+	for i, d := range team.Days {
+		dayIndex[d.Day] = i
+	}
+
+	for i := range team.Users {
+		user := &team.Users[i]
+
+		for j := range user.Days {
+			day := &user.Days[j]
+			seconds := credit[user.UserId][day.Day] * 3600
+
+			day.CreditedSeconds += seconds
+			user.CreditedSeconds += seconds
+			team.Days[dayIndex[day.Day]].CreditedSeconds += seconds
+			team.CreditedSeconds += seconds
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"time_tracker/internal/lib/workcal"
+)
+
+// WorkTime compares the expected working hours with the tracked hours and the hours
+// credited for approved absences. Overtime and undertime are the hours above and
+// below the expected ones.
+type WorkTime struct {
+	Expected  float64 `json:"expected_hours"`
+	Actual    float64 `json:"actual_hours"`
+	Credited  float64 `json:"credited_hours,omitempty"`
+	Overtime  float64 `json:"overtime_hours"`
+	Undertime float64 `json:"undertime_hours"`
+}
+
+type OvertimeDay struct {
+	Date string `json:"date"`
+	WorkTime
+}
+
+// OvertimePeriod is a week or a month of days. Its overtime and undertime are of the
+// period totals, so overtime of one day makes up for undertime of another.
+type OvertimePeriod struct {
+	Key   string    `json:"key"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	WorkTime
+	Days []OvertimeDay `json:"days"`
+}
+
+// Overtime compares the tracked and credited time of days, buckets of Summary by
+// PeriodDay, with the hours of the calendar scaled by the part-time factor of the user,
+// and groups the days by period. The total is of the whole report.
+func Overtime(days []Bucket, cal *workcal.Calendar, factor float64, period string) ([]OvertimePeriod, WorkTime) {
+	var (
+		periods                     []OvertimePeriod
+		expected, tracked, credited float64
+	)
+
+	for _, d := range days {
+		dayExpected := cal.Hours(d.Start) * factor
+		expected += dayExpected
+		tracked += d.Seconds
+		credited += d.CreditedSeconds
+
+		key := bucketKey(truncate(d.Start, period), period)
+
+		if len(periods) == 0 || periods[len(periods)-1].Key != key {
+			periods = append(periods, OvertimePeriod{
+				Key:   key,
+				Start: d.Start,
+			})
+		}
+
+		p := &periods[len(periods)-1]
+		p.End = d.End
+		p.Expected += dayExpected
+		p.Actual += d.Seconds / 3600
+		p.Credited += d.CreditedSeconds / 3600
+		p.Days = append(p.Days, OvertimeDay{
+			Date:     d.Key,
+			WorkTime: workTime(dayExpected, d.Seconds/3600, d.CreditedSeconds/3600),
+		})
+	}
+
+	for i := range periods {
+		periods[i].WorkTime = workTime(periods[i].Expected, periods[i].Actual, periods[i].Credited)
+	}
+
+	return periods, workTime(expected, tracked/3600, credited/3600)
+}
+
+// workTime rounds the hours to hundredths, which are below a minute.
+func workTime(expected, actual, credited float64) WorkTime {
+	w := WorkTime{
+		Expected: roundHours(expected),
+		Actual:   roundHours(actual),
+		Credited: roundHours(credited),
+	}
+
+	if worked := w.Actual + w.Credited; worked > w.Expected {
+		w.Overtime = roundHours(worked - w.Expected)
+	} else {
+		w.Undertime = roundHours(w.Expected - worked)
+	}
+
+	return w
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
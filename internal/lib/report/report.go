@@ -0,0 +1,159 @@
+package report
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"time_tracker/internal/storage/post"
+)
+
+const (
+	GroupByTask    = "task"
+	GroupByProject = "project"
+	GroupByTag     = "tag"
+)
+
+type Group struct {
+	ProjectId *int    `json:"project_id,omitempty"`
+	Tag       string  `json:"tag,omitempty"`
+	Hours     float64 `json:"hours"`
+	Minutes   float64 `json:"minutes"`
+	Seconds   float64 `json:"seconds"`
+}
+
+type Billing struct {
+	BillableSeconds    float64         `json:"billable_seconds"`
+	NonBillableSeconds float64         `json:"non_billable_seconds"`
+	Amount             decimal.Decimal `json:"amount" swaggertype:"string"`
+	Currency           string          `json:"currency"`
+	// MissingRate is set when billable time without an hourly rate was not counted.
+	MissingRate bool `json:"missing_rate,omitempty"`
+}
+
+// Bill splits task times into billable and non-billable time and sums the amounts.
+// The total is the sum of the rounded task amounts so that it matches the rows.
+func Bill(times []post.TaskTime, currency string) Billing {
+	billing := Billing{
+		Amount:   decimal.Zero,
+		Currency: currency,
+	}
+
+	for _, t := range times {
+		if !t.Billable {
+			billing.NonBillableSeconds += t.Seconds
+			continue
+		}
+
+		billing.BillableSeconds += t.Seconds
+		billing.Amount = billing.Amount.Add(t.Amount)
+		billing.MissingRate = billing.MissingRate || t.MissingRate
+	}
+
+	billing.Amount = billing.Amount.Round(2)
+
+	return billing
+}
+
+// HoursMinutes splits seconds the way task time reports do: fractional hours
+// and the minutes of the last started hour.
+func HoursMinutes(seconds float64) (float64, float64) {
+	return seconds / 3600, math.Mod(seconds, 3600) / 60
+}
+
+// ByTag sums task times per tag. A task with several tags is counted in each
+// of them, untagged tasks form a group with an empty tag.
+func ByTag(times []post.TaskTime) []Group {
+	groups := make(map[string]*Group)
+	var order []string
+
+	add := func(tag string, seconds float64) {
+		g, ok := groups[tag]
+		if !ok {
+			g = &Group{Tag: tag}
+			groups[tag] = g
+			order = append(order, tag)
+		}
+
+		g.Seconds += seconds
+	}
+
+	for _, t := range times {
+		// parents listed only for the time of their subtasks have nothing to add
+		if t.Seconds == 0 {
+			continue
+		}
+
+		if len(t.Tags) == 0 {
+			add("", t.Seconds)
+		}
+
+		for _, tag := range t.Tags {
+			add(tag, t.Seconds)
+		}
+	}
+
+	result := make([]Group, 0, len(order))
+	for _, tag := range order {
+		result = append(result, *groups[tag])
+	}
+
+	return finish(result)
+}
+
+// ByProject sums task times per project, tasks without a project form their own group.
+func ByProject(times []post.TaskTime) []Group {
+	groups := make(map[int]*Group)
+	var order []int
+
+	for _, t := range times {
+		if t.Seconds == 0 {
+			continue
+		}
+
+		key := 0
+		if t.ProjectId != nil {
+			key = *t.ProjectId
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{ProjectId: t.ProjectId}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		g.Seconds += t.Seconds
+	}
+
+	result := make([]Group, 0, len(order))
+	for _, key := range order {
+		result = append(result, *groups[key])
+	}
+
+	return finish(result)
+}
+
+// finish fills hours and minutes of the groups and puts the largest first.
+func finish(groups []Group) []Group {
+	for i := range groups {
+		groups[i].Hours, groups[i].Minutes = HoursMinutes(groups[i].Seconds)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Seconds > groups[j].Seconds
+	})
+
+	return groups
+}
+
+// TotalSeconds sums the time of the tasks themselves, so that subtasks are counted once.
+func TotalSeconds(times []post.TaskTime) float64 {
+	var total float64
+	for _, t := range times {
+		total += t.Seconds
+	}
+
+	return total
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"time_tracker/internal/storage/post"
+)
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+type Bucket struct {
+	// Key is the day (2024-03-05), the ISO week (2024-W10) or the month (2024-03).
+	Key   string    `json:"key"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Hours, Minutes and Seconds are the total of the bucket.
+	Hours   float64 `json:"hours"`
+	Minutes float64 `json:"minutes"`
+	Seconds float64 `json:"seconds"`
+	// CreditedSeconds is the time credited for approved absences, not part of Seconds.
+	CreditedSeconds float64       `json:"credited_seconds,omitempty"`
+	Items           []SummaryItem `json:"items,omitempty"`
+}
+
+// SummaryItem is the time of tasks with the same description and project in a bucket.
+type SummaryItem struct {
+	Description string  `json:"description"`
+	ProjectId   *int    `json:"project_id,omitempty"`
+	Hours       float64 `json:"hours"`
+	Minutes     float64 `json:"minutes"`
+	Seconds     float64 `json:"seconds"`
+}
+
+// Summary buckets sessions by day, ISO week or month between start and end, in the
+// location of start. Every bucket of the period is returned, empty ones too, and a
+// session crossing bucket boundaries is split between the buckets.
+func Summary(sessions []post.SessionTime, period string, start, end time.Time) []Bucket {
+	type bucket struct {
+		Bucket
+		items map[string]*SummaryItem
+		order []string
+	}
+
+	var buckets []*bucket
+	for b := truncate(start, period); b.Before(end); b = advance(b, period) {
+		buckets = append(buckets, &bucket{
+			Bucket: Bucket{
+				Key:   bucketKey(b, period),
+				Start: later(b, start),
+				End:   earlier(advance(b, period), end),
+			},
+			items: make(map[string]*SummaryItem),
+		})
+	}
+
+	for _, s := range sessions {
+		// buckets are ordered, skip the ones ending before the session
+		i := sort.Search(len(buckets), func(i int) bool {
+			return buckets[i].End.After(s.StartedAt)
+		})
+
+		for ; i < len(buckets) && buckets[i].Start.Before(s.StoppedAt); i++ {
+			b := buckets[i]
+			seconds := earlier(s.StoppedAt, b.End).Sub(later(s.StartedAt, b.Start)).Seconds()
+
+			key := s.Description + "\x00"
+			if s.ProjectId != nil {
+				key += strconv.Itoa(*s.ProjectId)
+			}
+
+			item, ok := b.items[key]
+			if !ok {
+				item = &SummaryItem{Description: s.Description, ProjectId: s.ProjectId}
+				b.items[key] = item
+				b.order = append(b.order, key)
+			}
+
+			item.Seconds += seconds
+			b.Seconds += seconds
+		}
+	}
+
+	result := make([]Bucket, 0, len(buckets))
+	for _, b := range buckets {
+		for _, key := range b.order {
+			item := b.items[key]
+			item.Hours, item.Minutes = HoursMinutes(item.Seconds)
+			b.Items = append(b.Items, *item)
+		}
+
+		sort.SliceStable(b.Items, func(i, j int) bool {
+			return b.Items[i].Seconds > b.Items[j].Seconds
+		})
+
+		b.Hours, b.Minutes = HoursMinutes(b.Seconds)
+		result = append(result, b.Bucket)
+	}
+
+	return result
+}
+
+// PeriodBounds returns the start of the day, ISO week or month t is in and the start
+// of the next one, in the location of t.
+func PeriodBounds(period string, t time.Time) (time.Time, time.Time) {
+	start := truncate(t, period)
+	return start, advance(start, period)
+}
+
+// truncate returns the start of the day, the Monday of the week or the first day of the month of t.
+func truncate(t time.Time, period string) time.Time {
+	year, month, day := t.Date()
+
+	switch period {
+	case PeriodWeek:
+		weekday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-weekday, 0, 0, 0, 0, t.Location())
+	case PeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func advance(t time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeek:
+		return t.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func bucketKey(t time.Time, period string) string {
+	switch period {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
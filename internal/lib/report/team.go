@@ -0,0 +1,89 @@
+package report
+
+import (
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/storage/post"
+)
+
+type Team struct {
+	Users []TeamUser `json:"users"`
+	// Days are the totals of all users per day.
+	Days    []DayTime `json:"days"`
+	Hours   float64   `json:"hours"`
+	Minutes float64   `json:"minutes"`
+	Seconds float64   `json:"seconds"`
+	// CreditedSeconds is the time credited for approved absences, not part of Seconds.
+	CreditedSeconds float64 `json:"credited_seconds,omitempty"`
+}
+
+type TeamUser struct {
+	UserId  int       `json:"user_id"`
+	Surname string    `json:"surname"`
+	Name    string    `json:"name"`
+	Days    []DayTime `json:"days"`
+	Hours   float64   `json:"hours"`
+	Minutes float64   `json:"minutes"`
+	Seconds float64   `json:"seconds"`
+	// CreditedSeconds is the time credited for approved absences, not part of Seconds.
+	CreditedSeconds float64 `json:"credited_seconds,omitempty"`
+}
+
+type DayTime struct {
+	Day     string  `json:"day"`
+	Hours   float64 `json:"hours"`
+	Minutes float64 `json:"minutes"`
+	Seconds float64 `json:"seconds"`
+	// CreditedSeconds is the time credited for approved absences, not part of Seconds.
+	CreditedSeconds float64 `json:"credited_seconds,omitempty"`
+}
+
+// TeamTime builds per user and per day totals from the days of the users,
+// which come ordered by user and day with every day of the period present.
+func TeamTime(days []post.TeamDay) Team {
+	team := Team{
+		Users: []TeamUser{},
+		Days:  []DayTime{},
+	}
+
+	dayIndex := make(map[string]int)
+
+	for _, d := range days {
+		if len(team.Users) == 0 || team.Users[len(team.Users)-1].UserId != d.UserId {
+			team.Users = append(team.Users, TeamUser{UserId: d.UserId, Surname: d.Surname, Name: d.Name})
+		}
+
+		user := &team.Users[len(team.Users)-1]
+		day := d.Day.Format(interval.DateLayout)
+
+		user.Days = append(user.Days, dayTime(day, d.Seconds))
+		user.Seconds += d.Seconds
+
+		i, ok := dayIndex[day]
+		if !ok {
+			i = len(team.Days)
+			dayIndex[day] = i
+			team.Days = append(team.Days, DayTime{Day: day})
+		}
+
+		team.Days[i].Seconds += d.Seconds
+		team.Seconds += d.Seconds
+	}
+
+	for i := range team.Users {
+		team.Users[i].Hours, team.Users[i].Minutes = HoursMinutes(team.Users[i].Seconds)
+	}
+
+	for i := range team.Days {
+		team.Days[i].Hours, team.Days[i].Minutes = HoursMinutes(team.Days[i].Seconds)
+	}
+
+	team.Hours, team.Minutes = HoursMinutes(team.Seconds)
+
+	return team
+}
+
+func dayTime(day string, seconds float64) DayTime {
+	hours, minutes := HoursMinutes(seconds)
+
+	return DayTime{Day: day, Hours: hours, Minutes: minutes, Seconds: seconds}
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"time_tracker/internal/storage/post"
+)
+
+// Timesheet is the time of one user per day, or per week or month, split by task.
+type Timesheet struct {
+	UserId int
+	Name   string
+	Rows   []Bucket
+}
+
+// TeamTimesheets builds a daily timesheet of every user from the sessions of the team.
+// from and to are the first and the last day of the period.
+func TeamTimesheets(sessions []post.TeamSession, from, to time.Time) ([]Timesheet, error) {
+	var (
+		sheets []Timesheet
+		user   []post.SessionTime
+	)
+
+	for i, s := range sessions {
+		if s.Description != nil {
+			user = append(user, post.SessionTime{
+				Description: *s.Description,
+				ProjectId:   s.ProjectId,
+				StartedAt:   s.StartedAt,
+				StoppedAt:   s.StoppedAt,
+			})
+		}
+
+		if i+1 < len(sessions) && sessions[i+1].UserId == s.UserId {
+			continue
+		}
+
+		loc, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone of user %d: %w", s.UserId, err)
+		}
+
+		start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+		end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+
+		sheets = append(sheets, Timesheet{
+			UserId: s.UserId,
+			Name:   strings.TrimSpace(s.Surname + " " + s.Name),
+			Rows:   Summary(user, PeriodDay, start, end),
+		})
+
+		user = nil
+	}
+
+	return sheets, nil
+}
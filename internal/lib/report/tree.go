@@ -0,0 +1,63 @@
+package report
+
+import "time_tracker/internal/storage/post"
+
+type Node struct {
+	TaskId      int             `json:"task_id"`
+	Description string          `json:"description"`
+	Status      post.TaskStatus `json:"status"`
+	// Hours and Minutes are the time of the task itself, the totals include its subtasks.
+	Hours        float64 `json:"hours"`
+	Minutes      float64 `json:"minutes"`
+	TotalHours   float64 `json:"total_hours"`
+	TotalMinutes float64 `json:"total_minutes"`
+	Children     []*Node `json:"children,omitempty"`
+	post.Estimate
+	seconds      float64
+	totalSeconds float64
+}
+
+// Tree links task nodes listed parents first into a tree and rolls the time of
+// the subtasks up into their parents. The first node is the root.
+func Tree(nodes []post.TaskNode) *Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	byId := make(map[int]*Node, len(nodes))
+	var order []*Node
+
+	for _, n := range nodes {
+		node := &Node{
+			TaskId:      n.TaskId,
+			Description: n.Description,
+			Status:      n.Status,
+			Estimate:    n.Estimate,
+			seconds:     n.Seconds,
+		}
+
+		if n.ParentId != nil {
+			if parent, ok := byId[*n.ParentId]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+
+		byId[n.TaskId] = node
+		order = append(order, node)
+	}
+
+	// children come after their parents, so walking backwards sums them first
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		node.totalSeconds = node.seconds
+
+		for _, child := range node.Children {
+			node.totalSeconds += child.totalSeconds
+		}
+
+		node.Hours, node.Minutes = HoursMinutes(node.seconds)
+		node.TotalHours, node.TotalMinutes = HoursMinutes(node.totalSeconds)
+	}
+
+	return order[0]
+}
@@ -0,0 +1,115 @@
+// Package workcal is the working calendar: the standard hours of every weekday and the
+// days that differ from them, like public holidays, shortened days before holidays
+// and weekends that are working days.
+package workcal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"time_tracker/internal/lib/interval"
+)
+
+var ErrInvalidWeek = errors.New("standard hours must be set for 7 weekdays from Monday to Sunday")
+
+type Calendar struct {
+	// week are the hours from Sunday to Saturday, in the order of time.Weekday.
+	week [7]float64
+	// days are the hours of the days that differ from the standard week by YYYY-MM-DD.
+	days map[string]float64
+}
+
+// New makes a calendar from the standard hours from Monday to Sunday and the hours of
+// the days that differ from them by YYYY-MM-DD day.
+func New(weekdayHours []float64, days map[string]float64) (*Calendar, error) {
+	if len(weekdayHours) != 7 {
+		return nil, ErrInvalidWeek
+	}
+
+	c := &Calendar{days: make(map[string]float64, len(days))}
+
+	for i, hours := range weekdayHours {
+		if hours < 0 || hours > 24 {
+			return nil, fmt.Errorf("invalid standard hours %v", hours)
+		}
+
+		c.week[(i+1)%7] = hours
+	}
+
+	for day, hours := range days {
+		c.days[day] = hours
+	}
+
+	return c, nil
+}
+
+// Load makes a calendar from the standard hours from Monday to Sunday and a file of the
+// days that differ from them, one per line: a YYYY-MM-DD day and its working hours, a
+// public holiday if the hours are omitted. Text after # is a comment:
+//
+//	2024-01-01        # New Year, a holiday
+//	2024-02-22 7      # shortened day before a holiday
+//	2024-04-27 8      # working Saturday
+//
+// An empty path makes a calendar of the standard week only.
+func Load(path string, weekdayHours []float64) (*Calendar, error) {
+	if path == "" {
+		return New(weekdayHours, nil)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open holidays: %w", err)
+	}
+	defer f.Close()
+
+	days := make(map[string]float64)
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("holidays line %d: expected a day and optional hours", n)
+		}
+
+		if _, err := time.Parse(interval.DateLayout, fields[0]); err != nil {
+			return nil, fmt.Errorf("holidays line %d: %w", n, interval.ErrInvalidDate)
+		}
+
+		hours := 0.0
+		if len(fields) == 2 {
+			hours, err = strconv.ParseFloat(fields[1], 64)
+			if err != nil || hours < 0 || hours > 24 {
+				return nil, fmt.Errorf("holidays line %d: invalid hours %q", n, fields[1])
+			}
+		}
+
+		days[fields[0]] = hours
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read holidays: %w", err)
+	}
+
+	return New(weekdayHours, days)
+}
+
+// Hours returns the standard working hours of the day, full time.
+func (c *Calendar) Hours(day time.Time) float64 {
+	if hours, ok := c.days[day.Format(interval.DateLayout)]; ok {
+		return hours
+	}
+
+	return c.week[day.Weekday()]
+}
@@ -0,0 +1,84 @@
+package reaper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"time_tracker/internal/lib/estimate"
+	"time_tracker/internal/lib/logger/sl"
+	"time_tracker/internal/storage/post"
+)
+
+type TimerReaper interface {
+	ReapTimers(ctx context.Context, maxDuration time.Duration, now time.Time) ([]post.AutoStopped, error)
+	CheckEstimates(ctx context.Context, taskId *int, thresholds []int, now time.Time) ([]post.EstimateAlert, error)
+}
+
+// Reaper periodically stops timers that were forgotten running and raises
+// estimate alerts of the tasks still running.
+type Reaper struct {
+	log                *slog.Logger
+	timerReaper        TimerReaper
+	interval           time.Duration
+	maxDuration        time.Duration
+	estimateThresholds []int
+}
+
+func New(log *slog.Logger, timerReaper TimerReaper, interval, maxDuration time.Duration, estimateThresholds []int) *Reaper {
+	return &Reaper{
+		log:                log.With(slog.String("component", "reaper")),
+		timerReaper:        timerReaper,
+		interval:           interval,
+		maxDuration:        maxDuration,
+		estimateThresholds: estimateThresholds,
+	}
+}
+
+// Run reaps timers every interval until ctx is done.
+func (r *Reaper) Run(ctx context.Context) {
+	r.log.Info("reaper started",
+		slog.String("interval", r.interval.String()),
+		slog.String("max_duration", r.maxDuration.String()),
+	)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reap(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.log.Info("reaper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reaper) reap(ctx context.Context) {
+	now := time.Now()
+
+	alerts, err := r.timerReaper.CheckEstimates(ctx, nil, r.estimateThresholds, now)
+	if err != nil {
+		r.log.Error("failed to check estimates", sl.Err(err))
+	}
+
+	estimate.Log(r.log, alerts)
+
+	stopped, err := r.timerReaper.ReapTimers(ctx, r.maxDuration, now)
+	if err != nil {
+		r.log.Error("failed to reap timers", sl.Err(err))
+		return
+	}
+
+	for _, s := range stopped {
+		r.log.Info("timer auto-stopped",
+			slog.Int("task_id", s.TaskId),
+			slog.Int("user_id", s.UserId),
+			slog.Time("started_at", s.StartedAt),
+			slog.Time("stopped_at", s.StoppedAt),
+		)
+	}
+}
@@ -0,0 +1,294 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"time_tracker/internal/storage"
+)
+
+type AbsenceStatus string
+
+const (
+	AbsenceRequested AbsenceStatus = "requested"
+	AbsenceApproved  AbsenceStatus = "approved"
+	AbsenceRejected  AbsenceStatus = "rejected"
+)
+
+const (
+	AbsenceVacation = "vacation"
+	AbsenceSick     = "sick"
+	AbsencePersonal = "personal"
+	AbsenceOther    = "other"
+)
+
+// Absence is a vacation, a sick leave or other days off of a user. StartDate and
+// EndDate are days in the time zone of the user, EndDate is the first day after the
+// absence. A half-day absence is a single day.
+type Absence struct {
+	Id            int           `json:"id"`
+	UserId        int           `json:"user_id"`
+	Type          string        `json:"type"`
+	StartDate     time.Time     `json:"start_date"`
+	EndDate       time.Time     `json:"end_date"`
+	HalfDay       bool          `json:"half_day"`
+	Status        AbsenceStatus `json:"status"`
+	Comment       *string       `json:"comment,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ReviewedBy    *int          `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time    `json:"reviewed_at,omitempty"`
+	ReviewComment *string       `json:"review_comment,omitempty"`
+}
+
+type NewAbsence struct {
+	UserId    int
+	Type      string
+	StartDate time.Time
+	EndDate   time.Time
+	HalfDay   bool
+	Comment   *string
+}
+
+// checkAbsenceLocked returns ErrPeriodLocked if the days from start to end, the first
+// day after them, intersect an approved timesheet of the user.
+func checkAbsenceLocked(ctx context.Context, tx pgx.Tx, userId int, start, end time.Time) error {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM timesheets
+		WHERE user_id = @user_id AND status = @approved
+		AND period_start < @end_date::date AND period_end > @start_date::date
+	)`
+
+	args := pgx.NamedArgs{
+		"user_id":    userId,
+		"approved":   TimesheetApproved,
+		"start_date": start,
+		"end_date":   end,
+	}
+
+	var locked bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked); err != nil {
+		return fmt.Errorf("unable to check locked periods: %w", err)
+	}
+
+	if locked {
+		return storage.ErrPeriodLocked
+	}
+
+	return nil
+}
+
+// CreateAbsence requests an absence of the user. It must not overlap other absences
+// of the user that are not rejected or an approved timesheet.
+func (pg *postgres) CreateAbsence(ctx context.Context, absence NewAbsence, now time.Time) (int, error) {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"user_id":    absence.UserId,
+		"type":       absence.Type,
+		"start_date": absence.StartDate,
+		"end_date":   absence.EndDate,
+		"half_day":   absence.HalfDay,
+		"comment":    absence.Comment,
+		"requested":  AbsenceRequested,
+		"rejected":   AbsenceRejected,
+		"now":        now,
+	}
+
+	// absences and timesheets of the same user are serialized on the user row
+	var userId int
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE id = @user_id FOR UPDATE`, args).Scan(&userId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return -1, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM absences
+		WHERE user_id = @user_id AND status <> @rejected
+		AND start_date < @end_date::date AND end_date > @start_date::date
+	)`
+
+	var overlap bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&overlap); err != nil {
+		return -1, fmt.Errorf("unable to select absences: %w", err)
+	}
+
+	if overlap {
+		return -1, storage.ErrAbsenceOverlap
+	}
+
+	if err := checkAbsenceLocked(ctx, tx, userId, absence.StartDate, absence.EndDate); err != nil {
+		return -1, err
+	}
+
+	query = `
+	INSERT INTO absences (user_id, type, start_date, end_date, half_day, status, comment, created_at)
+	VALUES (@user_id, @type, @start_date::date, @end_date::date, @half_day, @requested, @comment, @now)
+	RETURNING id`
+
+	var id int
+	if err := tx.QueryRow(ctx, query, args).Scan(&id); err != nil {
+		return -1, fmt.Errorf("unable to insert row: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// ReviewAbsence approves or rejects a requested absence on behalf of reviewedBy. An
+// absence in a period of an approved timesheet can not be approved.
+func (pg *postgres) ReviewAbsence(ctx context.Context, id int, reviewedBy int, approve bool, comment *string, now time.Time) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status := AbsenceRejected
+	if approve {
+		status = AbsenceApproved
+	}
+
+	args := pgx.NamedArgs{
+		"id":          id,
+		"reviewed_by": reviewedBy,
+		"status":      status,
+		"comment":     comment,
+		"now":         now,
+	}
+
+	var userId int
+	err = tx.QueryRow(ctx, `SELECT user_id FROM absences WHERE id = @id`, args).Scan(&userId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrAbsenceNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to select absence: %w", err)
+	}
+
+	args["user_id"] = userId
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = @user_id FOR UPDATE`, args); err != nil {
+		return fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	var (
+		current    AbsenceStatus
+		start, end time.Time
+	)
+
+	query := `SELECT status, start_date, end_date FROM absences WHERE id = @id FOR UPDATE`
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&current, &start, &end); err != nil {
+		return fmt.Errorf("unable to lock absence: %w", err)
+	}
+
+	if current != AbsenceRequested {
+		return storage.ErrNotRequested
+	}
+
+	if reviewedBy == userId {
+		return storage.ErrOwnAbsence
+	}
+
+	if approve {
+		if err := checkAbsenceLocked(ctx, tx, userId, start, end); err != nil {
+			return err
+		}
+	}
+
+	query = `
+	UPDATE absences SET status = @status, reviewed_by = @reviewed_by, reviewed_at = @now, review_comment = @comment
+	WHERE id = @id
+	`
+
+	_, err = tx.Exec(ctx, query, args)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAbsences returns the absences of the user, or of all users, optionally with the
+// status only, the latest first.
+func (pg *postgres) GetAbsences(ctx context.Context, userId *int, status *AbsenceStatus) ([]Absence, error) {
+	query := `
+	SELECT id, user_id, type, start_date, end_date, half_day, status, comment, created_at,
+	reviewed_by, reviewed_at, review_comment
+	FROM absences
+	WHERE (user_id = @user_id OR @user_id::int IS NULL) AND (status = @status OR @status::text IS NULL)
+	ORDER BY start_date DESC, user_id
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userId,
+		"status":  status,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Absence])
+}
+
+// GetApprovedAbsences returns the approved absences of the user intersecting the days
+// from start to end, the first day after them, in the order they start.
+func (pg *postgres) GetApprovedAbsences(ctx context.Context, userId int, start, end time.Time) ([]Absence, error) {
+	query := `
+	SELECT id, user_id, type, start_date, end_date, half_day, status, comment, created_at,
+	reviewed_by, reviewed_at, review_comment
+	FROM absences
+	WHERE user_id = @user_id AND status = @approved
+	AND start_date < @end_date::date AND end_date > @start_date::date
+	ORDER BY start_date
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":    userId,
+		"approved":   AbsenceApproved,
+		"start_date": start,
+		"end_date":   end,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Absence])
+}
@@ -0,0 +1,102 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
+)
+
+const (
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+type AuditRecord struct {
+	Id        int       `json:"id"`
+	TaskId    int       `json:"task_id"`
+	SessionId *int      `json:"session_id,omitempty"`
+	ChangedBy int       `json:"changed_by"`
+	Action    string    `json:"action"`
+	Field     *string   `json:"field,omitempty"`
+	OldValue  *string   `json:"old_value,omitempty"`
+	NewValue  *string   `json:"new_value,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (pg *postgres) GetTaskAudit(ctx context.Context, taskId int) ([]AuditRecord, error) {
+	query := `
+	SELECT id, task_id, session_id, changed_by, action, field, old_value, new_value, changed_at
+	FROM task_audit
+	WHERE task_id = @task_id
+	ORDER BY changed_at, id
+	`
+
+	args := pgx.NamedArgs{
+		"task_id": taskId,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[AuditRecord])
+}
+
+// writeAudit stores the records in the transaction of the change they describe.
+// task_audit has no foreign keys so that it outlives tasks, the users who made the
+// changes are checked here and ErrUserNotFound is returned for an unknown one.
+func writeAudit(ctx context.Context, tx pgx.Tx, records []AuditRecord) error {
+	checked := make(map[int]bool)
+
+	for _, record := range records {
+		if checked[record.ChangedBy] {
+			continue
+		}
+
+		args := pgx.NamedArgs{
+			"id": record.ChangedBy,
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = @id)`, args).Scan(&exists); err != nil {
+			return fmt.Errorf("unable to check audit user: %w", err)
+		}
+
+		if !exists {
+			return storage.ErrUserNotFound
+		}
+
+		checked[record.ChangedBy] = true
+	}
+
+	query := `
+	INSERT INTO task_audit (task_id, session_id, changed_by, action, field, old_value, new_value)
+	VALUES (@task_id, @session_id, @changed_by, @action, @field, @old_value, @new_value)
+	`
+
+	for _, record := range records {
+		args := pgx.NamedArgs{
+			"task_id":    record.TaskId,
+			"session_id": record.SessionId,
+			"changed_by": record.ChangedBy,
+			"action":     record.Action,
+			"field":      record.Field,
+			"old_value":  record.OldValue,
+			"new_value":  record.NewValue,
+		}
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return fmt.Errorf("unable to insert audit record: %w", err)
+		}
+	}
+
+	return nil
+}
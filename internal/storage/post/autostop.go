@@ -0,0 +1,146 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
+)
+
+type AutoStopped struct {
+	SessionId   int       `json:"session_id"`
+	TaskId      int       `json:"task_id"`
+	UserId      int       `json:"user_id"`
+	Description string    `json:"description"`
+	StartedAt   time.Time `json:"started_at"`
+	StoppedAt   time.Time `json:"stopped_at"`
+}
+
+// ReapTimers stops sessions running longer than maxDuration or past the end of day
+// of their user, in the time zone of the user. A session is stopped at whichever cut-off comes first, flagged as
+// auto-stopped and its task is paused and marked for review.
+//
+// The tasks of the sessions are locked before the sessions are updated, the order
+// StopTask and lockTask take the locks in, so a timer stopped while the reaper runs
+// waits instead of deadlocking.
+func (pg *postgres) ReapTimers(ctx context.Context, maxDuration time.Duration, now time.Time) ([]AutoStopped, error) {
+	query := `
+	WITH candidates AS (
+		SELECT task_sessions.id, LEAST(
+			task_sessions.started_at + @max_duration::interval,
+			(local.started_at::date + users.end_of_day
+				+ CASE WHEN local.started_at::time < users.end_of_day
+					THEN interval '0' ELSE interval '1 day' END) AT TIME ZONE users.time_zone
+		) AS cutoff
+		FROM task_sessions
+		JOIN tasks ON tasks.id = task_sessions.task_id
+		JOIN users ON users.id = tasks.user_id
+		CROSS JOIN LATERAL (
+			SELECT task_sessions.started_at AT TIME ZONE users.time_zone AS started_at
+		) local
+		WHERE task_sessions.stopped_at IS NULL
+		FOR UPDATE OF tasks
+	), stopped AS (
+		UPDATE task_sessions SET stopped_at = candidates.cutoff, auto_stopped = true
+		FROM candidates
+		WHERE task_sessions.id = candidates.id AND candidates.cutoff <= @now
+		AND task_sessions.stopped_at IS NULL
+		RETURNING task_sessions.id, task_sessions.task_id, task_sessions.started_at, task_sessions.stopped_at
+	), paused AS (
+		UPDATE tasks SET status = @status, needs_review = true
+		FROM stopped
+		WHERE tasks.id = stopped.task_id
+		RETURNING tasks.id, tasks.user_id, tasks.description
+	)
+	SELECT stopped.id AS session_id, stopped.task_id, paused.user_id, paused.description,
+	stopped.started_at, stopped.stopped_at
+	FROM stopped JOIN paused ON paused.id = stopped.task_id
+	`
+
+	args := pgx.NamedArgs{
+		"max_duration": maxDuration,
+		"now":          now,
+		"status":       TaskPaused,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[AutoStopped])
+}
+
+// GetAutoStopped returns auto-stopped sessions of tasks waiting for review,
+// of all users if userId is nil.
+func (pg *postgres) GetAutoStopped(ctx context.Context, userId *int) ([]AutoStopped, error) {
+	query := `
+	SELECT task_sessions.id AS session_id, tasks.id AS task_id, tasks.user_id, tasks.description,
+	task_sessions.started_at, task_sessions.stopped_at
+	FROM tasks
+	JOIN task_sessions ON task_sessions.task_id = tasks.id AND task_sessions.auto_stopped
+	WHERE tasks.needs_review AND (@user_id::int IS NULL OR tasks.user_id = @user_id)
+	ORDER BY task_sessions.started_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userId,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[AutoStopped])
+}
+
+// ReviewTask clears the review mark of an auto-stopped task.
+func (pg *postgres) ReviewTask(ctx context.Context, id int, changedBy int) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var needsReview bool
+	err = tx.QueryRow(ctx, `SELECT needs_review FROM tasks WHERE id = @id FOR UPDATE`, args).Scan(&needsReview)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrTaskNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to select task: %w", err)
+	}
+
+	if !needsReview {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE tasks SET needs_review = false WHERE id = @id`, args); err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	record := auditChange(id, nil, changedBy, "needs_review", "true", "false")
+
+	if err := writeAudit(ctx, tx, []AuditRecord{record}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,119 @@
+package post
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
+)
+
+// CalendarEntry is a session of a task shown in the calendar feed.
+type CalendarEntry struct {
+	SessionId   int
+	TaskId      int
+	Description string
+	ProjectId   *int
+	StartedAt   time.Time
+	// StoppedAt of a running session is the time of the request.
+	StoppedAt time.Time
+}
+
+// RotateFeedToken makes a new secret token of the calendar feed of the user, the
+// previous token stops working. Only a hash of the token is stored.
+func (pg *postgres) RotateFeedToken(ctx context.Context, userId int) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("unable to generate token: %w", err)
+	}
+
+	token := hex.EncodeToString(secret)
+
+	args := pgx.NamedArgs{
+		"id":   userId,
+		"hash": feedTokenHash(token),
+	}
+
+	results, err := pg.db.Exec(ctx, `UPDATE users SET feed_token_hash = @hash WHERE id = @id`, args)
+
+	if err != nil {
+		return "", fmt.Errorf("unable to update row: %w", err)
+	}
+
+	if results.RowsAffected() == 0 {
+		return "", storage.ErrUserNotFound
+	}
+
+	return token, nil
+}
+
+// GetFeedUser returns the id and the time zone of the user the feed token belongs to.
+func (pg *postgres) GetFeedUser(ctx context.Context, token string) (int, *time.Location, error) {
+	args := pgx.NamedArgs{
+		"hash": feedTokenHash(token),
+	}
+
+	var (
+		id       int
+		timeZone string
+	)
+
+	err := pg.db.QueryRow(ctx, `SELECT id, time_zone FROM users WHERE feed_token_hash = @hash`, args).Scan(&id, &timeZone)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to select user: %w", err)
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid time zone of user %d: %w", id, err)
+	}
+
+	return id, loc, nil
+}
+
+// GetCalendarEntries returns the sessions of the user that intersect the period, not
+// clipped, in the order they started.
+func (pg *postgres) GetCalendarEntries(ctx context.Context, userId int, startPeriod, endPeriod, now time.Time) ([]CalendarEntry, error) {
+	query := `
+	SELECT task_sessions.id AS session_id, tasks.id AS task_id, tasks.description, tasks.project_id,
+	started_at, COALESCE(stopped_at, @now) AS stopped_at
+	FROM tasks
+	JOIN task_sessions ON task_sessions.task_id = tasks.id
+	WHERE tasks.user_id = @user_id AND ` + sessionInPeriod + `
+	ORDER BY started_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":         userId,
+		"start_period":    startPeriod,
+		"end_period":      endPeriod,
+		"include_running": true,
+		"now":             now,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[CalendarEntry])
+}
+
+func feedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
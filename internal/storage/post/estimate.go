@@ -0,0 +1,100 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Estimate compares the planned duration of a task with the time tracked on it.
+type Estimate struct {
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// ActualMinutes is the time tracked on the task in all periods, RemainingMinutes
+	// is negative when the estimate is exceeded. Both are set only with an estimate.
+	ActualMinutes    *float64 `json:"actual_minutes,omitempty" db:"-"`
+	RemainingMinutes *float64 `json:"remaining_minutes,omitempty" db:"-"`
+	// EstimateReached is the highest alert threshold in percent the task has crossed.
+	EstimateReached int     `json:"estimate_reached,omitempty"`
+	TrackedSeconds  float64 `json:"-"`
+}
+
+func (e *Estimate) fill() {
+	if e.EstimateMinutes == nil {
+		return
+	}
+
+	actual := e.TrackedSeconds / 60
+	remaining := float64(*e.EstimateMinutes) - actual
+
+	e.ActualMinutes, e.RemainingMinutes = &actual, &remaining
+}
+
+// trackedSeconds is the time of all finished sessions of the task.
+const trackedSeconds = `COALESCE((
+		SELECT SUM(EXTRACT(EPOCH FROM (tracked.stopped_at - tracked.started_at)))
+		FROM task_sessions tracked
+		WHERE tracked.task_id = tasks.id AND tracked.stopped_at IS NOT NULL
+	), 0)`
+
+type EstimateAlert struct {
+	TaskId          int     `json:"task_id"`
+	UserId          int     `json:"user_id"`
+	Description     string  `json:"description"`
+	EstimateMinutes int     `json:"estimate_minutes"`
+	Threshold       int     `json:"threshold"`
+	TrackedSeconds  float64 `json:"tracked_seconds"`
+}
+
+// CheckEstimates sets the estimate_reached flag of tasks to the highest of the thresholds
+// given in percent of the estimate their tracked time, running sessions counted up to
+// now, crossed. The flag is lowered too when sessions are shortened or the estimate is
+// raised. Only the task is checked if taskId is set, otherwise all running tasks.
+// Tasks whose flag was raised are reported, once per threshold, with the highest
+// threshold crossed.
+func (pg *postgres) CheckEstimates(ctx context.Context, taskId *int, thresholds []int, now time.Time) ([]EstimateAlert, error) {
+	query := `
+	WITH tracked AS (
+		SELECT tasks.id AS task_id, tasks.estimate_minutes, tasks.estimate_reached,
+		COALESCE(SUM(EXTRACT(EPOCH FROM (COALESCE(stopped_at, @now) - started_at))), 0) AS seconds
+		FROM tasks
+		LEFT JOIN task_sessions ON task_sessions.task_id = tasks.id
+		WHERE tasks.estimate_minutes IS NOT NULL
+		AND (tasks.id = @task_id OR (@task_id::int IS NULL AND tasks.status = @status))
+		GROUP BY tasks.id
+	), reached AS (
+		SELECT tracked.task_id, tracked.seconds, tracked.estimate_reached AS previous,
+		COALESCE(MAX(threshold) FILTER (
+			WHERE tracked.seconds >= tracked.estimate_minutes * 60 * threshold / 100.0
+		), 0) AS threshold
+		FROM tracked, unnest(@thresholds::int[]) AS threshold
+		GROUP BY tracked.task_id, tracked.seconds, tracked.estimate_reached
+	), updated AS (
+		UPDATE tasks SET estimate_reached = reached.threshold
+		FROM reached
+		WHERE tasks.id = reached.task_id AND tasks.estimate_reached <> reached.threshold
+		RETURNING tasks.id AS task_id, tasks.user_id, tasks.description, tasks.estimate_minutes,
+		reached.threshold, reached.seconds AS tracked_seconds, reached.previous
+	)
+	SELECT task_id, user_id, description, estimate_minutes, threshold, tracked_seconds
+	FROM updated
+	WHERE threshold > previous
+	`
+
+	args := pgx.NamedArgs{
+		"task_id":    taskId,
+		"status":     TaskRunning,
+		"thresholds": intArray(thresholds),
+		"now":        now,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[EstimateAlert])
+}
@@ -0,0 +1,248 @@
+package post
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
+)
+
+const (
+	SortStartTime = "start_time"
+	SortDuration  = "duration"
+
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+type TaskFilter struct {
+	UserId *int
+	Status *TaskStatus
+	// From and To select tasks with a session intersecting the period.
+	From        *time.Time
+	To          *time.Time
+	Description *string
+	SortBy      string
+	Desc        bool
+	Cursor      string
+	Limit       int
+}
+
+type TaskRow struct {
+	Id              int        `json:"id"`
+	UserId          int        `json:"user_id"`
+	Description     string     `json:"description"`
+	Status          TaskStatus `json:"status"`
+	StartTime       *time.Time `json:"start_time"`
+	EndTime         *time.Time `json:"end_time"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	SortKey         float64    `json:"-"`
+	Estimate
+}
+
+// ListTasks returns one page of tasks matching the filter and the cursor of the next page,
+// empty on the last page. Durations of running sessions are counted up to now.
+func (pg *postgres) ListTasks(ctx context.Context, filter TaskFilter, now time.Time) ([]TaskRow, string, error) {
+	query, args, err := listQuery(filter, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	query += ` LIMIT @limit`
+	args["limit"] = limit + 1
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer rows.Close()
+	result, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskRow])
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range result {
+		result[i].fill()
+	}
+
+	if len(result) <= limit {
+		return result, "", nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1]
+
+	return result, encodeCursor(last.SortKey, last.Id), nil
+}
+
+// StreamTasks calls fn for every task matching the filter, starting after the cursor,
+// without loading them all at once. The limit of the filter is ignored.
+func (pg *postgres) StreamTasks(ctx context.Context, filter TaskFilter, now time.Time, fn func(TaskRow) error) error {
+	query, args, err := listQuery(filter, now)
+	if err != nil {
+		return err
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := pgx.RowToStructByName[TaskRow](rows)
+		if err != nil {
+			return err
+		}
+
+		row.fill()
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func listQuery(filter TaskFilter, now time.Time) (string, pgx.NamedArgs, error) {
+	args := pgx.NamedArgs{
+		"now": now,
+	}
+
+	var conditions []string
+
+	if filter.UserId != nil {
+		conditions = append(conditions, "tasks.user_id = @user_id")
+		args["user_id"] = *filter.UserId
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "tasks.status = @status")
+		args["status"] = *filter.Status
+	}
+	if filter.Description != nil {
+		conditions = append(conditions, "tasks.description ILIKE '%' || @description || '%'")
+		args["description"] = escapeLike(*filter.Description)
+	}
+	if filter.From != nil || filter.To != nil {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM task_sessions period
+			WHERE period.task_id = tasks.id
+			AND (@to::timestamptz IS NULL OR period.started_at < @to)
+			AND (@from::timestamptz IS NULL OR COALESCE(period.stopped_at, @now) > @from)
+		)`)
+		args["from"] = filter.From
+		args["to"] = filter.To
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var sortKey string
+	switch filter.SortBy {
+	case "", SortStartTime:
+		// tasks that were never started go last
+		sortKey = "COALESCE(EXTRACT(EPOCH FROM start_time)::float8, 'Infinity'::float8)"
+	case SortDuration:
+		sortKey = "duration_seconds"
+	default:
+		return "", nil, fmt.Errorf("%w: %s", storage.ErrInvalidSort, filter.SortBy)
+	}
+
+	order, cmp := "ASC", ">"
+	if filter.Desc {
+		order, cmp = "DESC", "<"
+	}
+
+	after := ""
+	if filter.Cursor != "" {
+		key, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+
+		after = fmt.Sprintf("WHERE (sort_key, id) %s (@cursor_key, @cursor_id)", cmp)
+		args["cursor_key"] = key
+		args["cursor_id"] = id
+	}
+
+	query := fmt.Sprintf(`
+	WITH task_rows AS (
+		SELECT tasks.id, tasks.user_id, tasks.description, tasks.status,
+		tasks.estimate_minutes, tasks.estimate_reached,
+		MIN(task_sessions.started_at) AS start_time,
+		CASE WHEN COUNT(task_sessions.id) = COUNT(task_sessions.stopped_at)
+			THEN MAX(task_sessions.stopped_at) END AS end_time,
+		COALESCE(SUM(EXTRACT(EPOCH FROM (COALESCE(task_sessions.stopped_at, @now) - task_sessions.started_at))), 0)::float8 AS duration_seconds
+		FROM tasks
+		LEFT JOIN task_sessions ON task_sessions.task_id = tasks.id
+		%s
+		GROUP BY tasks.id
+	), keyed AS (
+		SELECT *, %s AS sort_key FROM task_rows
+	)
+	SELECT id, user_id, description, status, start_time, end_time, duration_seconds, sort_key,
+	estimate_minutes, estimate_reached, duration_seconds AS tracked_seconds
+	FROM keyed
+	%s
+	ORDER BY sort_key %s, id %s
+	`, where, sortKey, after, order, order)
+
+	return query, args, nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// encodeCursor packs the sort key and id of the last row of a page.
+func encodeCursor(key float64, id int) string {
+	raw := strconv.FormatFloat(key, 'g', -1, 64) + ":" + strconv.Itoa(id)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (float64, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, storage.ErrInvalidCursor
+	}
+
+	keyStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, 0, storage.ErrInvalidCursor
+	}
+
+	key, err := strconv.ParseFloat(keyStr, 64)
+	if err != nil || math.IsNaN(key) {
+		return 0, 0, storage.ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, 0, storage.ErrInvalidCursor
+	}
+
+	return key, id, nil
+}
@@ -37,4 +37,10 @@ func (pg *postgres) Ping(ctx context.Context) error {
 
 func (pg *postgres) Close() {
 	pg.db.Close()
-}
\ No newline at end of file
+}
+
+// PostgreSQL error codes handled by the storage.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
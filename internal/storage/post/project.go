@@ -0,0 +1,121 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"time_tracker/internal/storage"
+)
+
+type Project struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (pg *postgres) CreateProject(ctx context.Context, name, description string) (int, error) {
+	query := `
+	INSERT INTO projects (name, description)
+	VALUES (@name, @description) RETURNING id`
+
+	args := pgx.NamedArgs{
+		"name":        name,
+		"description": description,
+	}
+
+	var id int
+	err := pg.db.QueryRow(ctx, query, args).Scan(&id)
+
+	if isUniqueViolation(err) {
+		return -1, storage.ErrProjectExists
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("unable to insert row: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetProjects returns the project with the id or all projects if id is nil.
+func (pg *postgres) GetProjects(ctx context.Context, id *int) ([]Project, error) {
+	query := `
+	SELECT id, name, description FROM projects
+	WHERE @id::int IS NULL OR id = @id
+	ORDER BY name
+	`
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Project])
+}
+
+func (pg *postgres) UpdateProject(ctx context.Context, id int, name, description string) error {
+	query := `
+	UPDATE projects SET name = @name, description = @description
+	WHERE id = @id
+	`
+
+	args := pgx.NamedArgs{
+		"id":          id,
+		"name":        name,
+		"description": description,
+	}
+
+	results, err := pg.db.Exec(ctx, query, args)
+
+	if isUniqueViolation(err) {
+		return storage.ErrProjectExists
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	if results.RowsAffected() == 0 {
+		return storage.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// DeleteProject removes the project, its tasks are kept without a project.
+func (pg *postgres) DeleteProject(ctx context.Context, id int) error {
+	query := `DELETE FROM projects WHERE id = @id`
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	results, err := pg.db.Exec(ctx, query, args)
+
+	if err != nil {
+		return fmt.Errorf("unable to delete row: %w", err)
+	}
+
+	if results.RowsAffected() == 0 {
+		return storage.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,101 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+
+	"time_tracker/internal/storage"
+)
+
+// Rate is an hourly rate of a user, a project or a user on a project,
+// applied to sessions started on or after EffectiveFrom.
+type Rate struct {
+	Id            int             `json:"id"`
+	UserId        *int            `json:"user_id,omitempty"`
+	ProjectId     *int            `json:"project_id,omitempty"`
+	Rate          decimal.Decimal `json:"rate" swaggertype:"string"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+}
+
+// SetRate creates the rate or replaces the rate with the same owner and start date.
+func (pg *postgres) SetRate(ctx context.Context, rate Rate) (int, error) {
+	query := `
+	INSERT INTO hourly_rates (user_id, project_id, rate, effective_from)
+	VALUES (@user_id, @project_id, @rate, @effective_from)
+	ON CONFLICT (COALESCE(user_id, 0), COALESCE(project_id, 0), effective_from)
+	DO UPDATE SET rate = EXCLUDED.rate
+	RETURNING id`
+
+	args := pgx.NamedArgs{
+		"user_id":        rate.UserId,
+		"project_id":     rate.ProjectId,
+		"rate":           rate.Rate,
+		"effective_from": rate.EffectiveFrom,
+	}
+
+	var id int
+	err := pg.db.QueryRow(ctx, query, args).Scan(&id)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		if pgErr.ConstraintName == "hourly_rates_project_id_fkey" {
+			return -1, storage.ErrProjectNotFound
+		}
+
+		return -1, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("unable to insert row: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetRates returns rates of the user and of the project, all rates if both are nil.
+func (pg *postgres) GetRates(ctx context.Context, userId, projectId *int) ([]Rate, error) {
+	query := `
+	SELECT id, user_id, project_id, rate, effective_from
+	FROM hourly_rates
+	WHERE (@user_id::int IS NULL OR user_id = @user_id)
+	AND (@project_id::int IS NULL OR project_id = @project_id)
+	ORDER BY user_id, project_id, effective_from
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":    userId,
+		"project_id": projectId,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Rate])
+}
+
+// sessionRate joins the hourly rate of each session of billable tasks: the most
+// specific rate effective on the day the session started in the time zone of the user.
+const sessionRate = `
+	LEFT JOIN LATERAL (
+		SELECT hourly_rates.rate FROM hourly_rates
+		WHERE tasks.billable AND hourly_rates.effective_from <= (task_sessions.started_at AT TIME ZONE (
+			SELECT time_zone FROM users WHERE users.id = tasks.user_id
+		))::date
+		AND (hourly_rates.user_id = tasks.user_id OR hourly_rates.user_id IS NULL)
+		AND (hourly_rates.project_id = tasks.project_id OR hourly_rates.project_id IS NULL)
+		ORDER BY hourly_rates.user_id IS NOT NULL AND hourly_rates.project_id IS NOT NULL DESC,
+		hourly_rates.project_id IS NOT NULL DESC,
+		hourly_rates.effective_from DESC
+		LIMIT 1
+	) rates ON true`
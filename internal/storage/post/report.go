@@ -0,0 +1,205 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type TaskTime struct {
+	TaskID      int      `json:"task_id"`
+	Description string   `json:"description"`
+	ProjectId   *int     `json:"project_id,omitempty"`
+	ParentId    *int     `json:"parent_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Hours       float64  `json:"hours"`
+	Minutes     float64  `json:"minutes"`
+	Seconds     float64  `json:"seconds"`
+	// TotalHours, TotalMinutes and TotalSeconds add up the time of the task and all its subtasks.
+	TotalHours   float64 `json:"total_hours"`
+	TotalMinutes float64 `json:"total_minutes"`
+	TotalSeconds float64 `json:"total_seconds"`
+	Billable     bool    `json:"billable"`
+	// Amount is earned by a billable task, rounded to cents. MissingRate is set
+	// when some of its sessions have no hourly rate and were not counted.
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string"`
+	MissingRate bool            `json:"missing_rate,omitempty"`
+	Estimate
+}
+
+type ReportFilter struct {
+	UserId      int
+	StartPeriod time.Time
+	EndPeriod   time.Time
+	// ProjectIds limits the report to tasks of the projects, all tasks if empty.
+	ProjectIds []int
+	// Tags limits the report to tasks having any of the tags, all tasks if empty.
+	Tags []string
+	// IncludeRunning counts open sessions up to Now.
+	IncludeRunning bool
+	Now            time.Time
+}
+
+type ProjectTimeFilter struct {
+	// ProjectIds and UserIds do not limit the report if empty.
+	ProjectIds     []int
+	UserIds        []int
+	StartPeriod    time.Time
+	EndPeriod      time.Time
+	IncludeRunning bool
+	Now            time.Time
+}
+
+type ProjectTime struct {
+	ProjectId int     `json:"project_id"`
+	Name      string  `json:"name"`
+	Users     int     `json:"users"`
+	Hours     float64 `json:"hours"`
+	Minutes   float64 `json:"minutes"`
+	Seconds   float64 `json:"seconds"`
+}
+
+// sessionInPeriod selects sessions intersecting a report period, open sessions
+// only with @include_running.
+const sessionInPeriod = `started_at < @end_period
+	AND COALESCE(stopped_at, @now) > @start_period
+	AND (stopped_at IS NOT NULL OR @include_running)`
+
+// sessionSeconds is the part of a session inside the report period, an open
+// session lasting until now.
+const sessionSeconds = `EXTRACT(EPOCH FROM (
+	LEAST(COALESCE(stopped_at, @now), @end_period) - GREATEST(started_at, @start_period)
+))`
+
+// taskInFilter selects tasks of the projects and tags of a report filter.
+const taskInFilter = `(cardinality(@project_ids::int[]) = 0 OR tasks.project_id = ANY(@project_ids))
+	AND (cardinality(@tags::text[]) = 0 OR EXISTS (
+		SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id AND tags.name = ANY(@tags)
+	))`
+
+// GetUserTaskTime reports the time of each task and the total including its subtasks.
+// Tasks without time of their own are listed when their subtasks have time.
+func (pg *postgres) GetUserTaskTime(ctx context.Context, filter ReportFilter) ([]TaskTime, error) {
+	query := `
+	WITH RECURSIVE own AS (
+		SELECT tasks.id AS task_id,
+		SUM(` + sessionSeconds + `) AS seconds,
+		ROUND(COALESCE(SUM(` + sessionSeconds + ` * rates.rate / 3600), 0), 2) AS amount,
+		COALESCE(bool_or(tasks.billable AND rates.rate IS NULL), false) AS missing_rate
+		FROM tasks
+		JOIN task_sessions ON task_sessions.task_id = tasks.id
+		` + sessionRate + `
+		WHERE tasks.user_id = @user_id AND ` + sessionInPeriod + ` AND ` + taskInFilter + `
+		GROUP BY tasks.id
+	), descendants AS (
+		SELECT id AS ancestor_id, id AS task_id FROM tasks WHERE user_id = @user_id
+		UNION ALL
+		SELECT descendants.ancestor_id, tasks.id FROM tasks
+		JOIN descendants ON tasks.parent_id = descendants.task_id
+	), total AS (
+		SELECT descendants.ancestor_id AS task_id, SUM(own.seconds) AS seconds
+		FROM descendants
+		JOIN own ON own.task_id = descendants.task_id
+		GROUP BY descendants.ancestor_id
+	)
+	SELECT tasks.id AS task_id, tasks.description, tasks.project_id, tasks.parent_id,
+	ARRAY(
+		SELECT tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id ORDER BY tags.name
+	) AS tags,
+	COALESCE(own.seconds, 0) / 3600 AS hours,
+	(COALESCE(own.seconds, 0) % 3600) / 60 AS minutes,
+	COALESCE(own.seconds, 0) AS seconds,
+	total.seconds / 3600 AS total_hours,
+	(total.seconds % 3600) / 60 AS total_minutes,
+	total.seconds AS total_seconds,
+	tasks.billable,
+	COALESCE(own.amount, 0) AS amount,
+	COALESCE(own.missing_rate, false) AS missing_rate,
+	tasks.estimate_minutes, tasks.estimate_reached, ` + trackedSeconds + ` AS tracked_seconds
+	FROM total
+	JOIN tasks ON tasks.id = total.task_id
+	LEFT JOIN own ON own.task_id = tasks.id
+	ORDER BY hours, minutes DESC
+	`
+	rows, err := pg.db.Query(ctx, query, filter.args())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	result, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskTime])
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].fill()
+	}
+
+	return result, err
+}
+
+func (filter ReportFilter) args() pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"user_id":         filter.UserId,
+		"start_period":    filter.StartPeriod,
+		"end_period":      filter.EndPeriod,
+		"project_ids":     intArray(filter.ProjectIds),
+		"tags":            normalizeTags(filter.Tags),
+		"include_running": filter.IncludeRunning,
+		"now":             filter.Now,
+	}
+}
+
+// GetProjectTime sums the time of all users per project.
+func (pg *postgres) GetProjectTime(ctx context.Context, filter ProjectTimeFilter) ([]ProjectTime, error) {
+	query := `
+	SELECT projects.id AS project_id, projects.name,
+	COUNT(DISTINCT tasks.user_id) AS users,
+	SUM(` + sessionSeconds + `) / 3600 AS hours,
+	(SUM(` + sessionSeconds + `) % 3600) / 60 AS minutes,
+	SUM(` + sessionSeconds + `) AS seconds
+	FROM projects
+	JOIN tasks ON tasks.project_id = projects.id
+	JOIN task_sessions ON task_sessions.task_id = tasks.id
+	WHERE ` + sessionInPeriod + `
+	AND (cardinality(@project_ids::int[]) = 0 OR projects.id = ANY(@project_ids))
+	AND (cardinality(@user_ids::int[]) = 0 OR tasks.user_id = ANY(@user_ids))
+	GROUP BY projects.id
+	ORDER BY hours DESC
+	`
+
+	args := pgx.NamedArgs{
+		"start_period":    filter.StartPeriod,
+		"end_period":      filter.EndPeriod,
+		"project_ids":     intArray(filter.ProjectIds),
+		"user_ids":        intArray(filter.UserIds),
+		"include_running": filter.IncludeRunning,
+		"now":             filter.Now,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[ProjectTime])
+}
+
+// intArray makes sure a nil slice is sent as an empty array, not NULL.
+func intArray(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+
+	return ids
+}
@@ -0,0 +1,41 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionTime is a session of a task clipped to a report period.
+type SessionTime struct {
+	TaskId      int
+	Description string
+	ProjectId   *int
+	StartedAt   time.Time
+	StoppedAt   time.Time
+}
+
+// GetUserSessions returns the sessions of the user in the period of the filter,
+// clipped to the period, in the order they started.
+func (pg *postgres) GetUserSessions(ctx context.Context, filter ReportFilter) ([]SessionTime, error) {
+	query := `
+	SELECT tasks.id AS task_id, tasks.description, tasks.project_id,
+	GREATEST(started_at, @start_period) AS started_at,
+	LEAST(COALESCE(stopped_at, @now), @end_period) AS stopped_at
+	FROM tasks
+	JOIN task_sessions ON task_sessions.task_id = tasks.id
+	WHERE tasks.user_id = @user_id AND ` + sessionInPeriod + ` AND ` + taskInFilter + `
+	ORDER BY started_at
+	`
+
+	rows, err := pg.db.Query(ctx, query, filter.args())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[SessionTime])
+}
@@ -0,0 +1,107 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MaxTagLength is the longest tag name in characters, the length of tags.name.
+const MaxTagLength = 50
+
+// AttachTags adds the tags to the task, creating tags that do not exist yet.
+func (pg *postgres) AttachTags(ctx context.Context, taskId int, names []string) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the owner is locked first, an approval of its timesheet must not interleave
+	if _, err := lockTask(ctx, tx, taskId); err != nil {
+		return err
+	}
+
+	// tags of a task with time in an approved timesheet change its reports
+	if err := checkTaskLocked(ctx, tx, taskId, time.Now()); err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"task_id": taskId,
+		"names":   normalizeTags(names),
+	}
+
+	query := `
+	INSERT INTO tags (name) SELECT unnest(@names::text[])
+	ON CONFLICT (name) DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("unable to insert tags: %w", err)
+	}
+
+	query = `
+	INSERT INTO task_tags (task_id, tag_id)
+	SELECT @task_id, id FROM tags WHERE name = ANY(@names::text[])
+	ON CONFLICT DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("unable to attach tags: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DetachTags removes the tags from the task, tags the task does not have are skipped.
+func (pg *postgres) DetachTags(ctx context.Context, taskId int, names []string) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// deleting from task_tags affects no rows for a missing task as well
+	if _, err := lockTask(ctx, tx, taskId); err != nil {
+		return err
+	}
+
+	if err := checkTaskLocked(ctx, tx, taskId, time.Now()); err != nil {
+		return err
+	}
+
+	query := `
+	DELETE FROM task_tags
+	USING tags
+	WHERE task_tags.tag_id = tags.id AND task_tags.task_id = @task_id AND tags.name = ANY(@names::text[])
+	`
+
+	args := pgx.NamedArgs{
+		"task_id": taskId,
+		"names":   normalizeTags(names),
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("unable to detach tags: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// normalizeTags lower-cases and trims tag names, dropping empty ones.
+func normalizeTags(names []string) []string {
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -4,99 +4,819 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"time_tracker/internal/lib/interval"
+	"time_tracker/internal/storage"
 )
 
-type TaskTime struct {
-	TaskID  int `json:"task_id"`
-	Hours   float64 `json:"hours"`
-	Minutes float64 `json:"minutes"`
+type RunningTask struct {
+	TaskId      int       `json:"task_id"`
+	Description string    `json:"description"`
+	StartedAt   time.Time `json:"started_at"`
+	// ElapsedSeconds is the duration of the open session, TotalSeconds includes earlier sessions.
+	ElapsedSeconds float64 `json:"elapsed_seconds" db:"-"`
+	TotalSeconds   float64 `json:"total_seconds" db:"-"`
+	Estimate
 }
 
-func (pg *postgres) CreateTask(ctx context.Context, userId int, description string) (int, error) {
-	query := `
-	INSERT INTO tasks (user_id, description) 
-	VALUES (@user_id, @description) RETURNING id`
+type NewTask struct {
+	UserId      int
+	Description string
+	ProjectId   *int
+	// ParentId makes the task a subtask of another task of the same user.
+	ParentId *int
+	Billable bool
+	// EstimateMinutes is the planned duration of the task.
+	EstimateMinutes *int
+	// ExternalUid is the UID of the calendar event the task is imported from.
+	ExternalUid *string
+}
 
+// insertTask inserts a task unless its parent is not a task of the same user,
+// in which case no row is returned.
+const insertTask = `
+	INSERT INTO tasks (user_id, description, project_id, parent_id, billable, estimate_minutes, status, external_uid)
+	SELECT @user_id::int, @description::text, @project_id::int, @parent_id::int, @billable::bool,
+	@estimate_minutes::int, @status::text, @external_uid::text
+	WHERE @parent_id::int IS NULL OR EXISTS (
+		SELECT 1 FROM tasks WHERE id = @parent_id AND user_id = @user_id
+	) RETURNING id`
+
+func (pg *postgres) CreateTask(ctx context.Context, task NewTask) (int, error) {
 	args := pgx.NamedArgs{
-		"user_id":     userId,
-		"description": description,
+		"user_id":          task.UserId,
+		"description":      task.Description,
+		"project_id":       task.ProjectId,
+		"parent_id":        task.ParentId,
+		"billable":         task.Billable,
+		"status":           TaskCreated,
+		"estimate_minutes": task.EstimateMinutes,
+		"external_uid":     task.ExternalUid,
 	}
 
-	result := pg.db.QueryRow(ctx, query, args)
+	result := pg.db.QueryRow(ctx, insertTask, args)
 
 	var id int
 	err := result.Scan(&id)
 
 	if err != nil {
+		return -1, insertTaskErr(err)
+	}
+
+	return id, nil
+}
+
+// insertTaskErr maps a missing parent and foreign key violations of a new task to storage errors.
+func insertTaskErr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrParentNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		switch pgErr.ConstraintName {
+		case "tasks_user_id_fkey":
+			return storage.ErrUserNotFound
+		case "tasks_project_id_fkey":
+			return storage.ErrProjectNotFound
+		}
+	}
+
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "tasks_user_id_external_uid_key" {
+		return storage.ErrAlreadyImported
+	}
+
+	return fmt.Errorf("unable to insert row: %w", err)
+}
+
+// CreateTimeEntry records work done in the past as a finished task with a single session.
+// Unless allowOverlap is set the entry must not overlap other sessions of the user.
+func (pg *postgres) CreateTimeEntry(ctx context.Context, task NewTask, startTime, endTime time.Time, allowOverlap bool) (int, error) {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"user_id":          task.UserId,
+		"description":      task.Description,
+		"project_id":       task.ProjectId,
+		"parent_id":        task.ParentId,
+		"billable":         task.Billable,
+		"status":           TaskDone,
+		"estimate_minutes": task.EstimateMinutes,
+		"external_uid":     task.ExternalUid,
+		"started_at":       startTime,
+		"stopped_at":       endTime,
+	}
+
+	var userId int
+
+	// entries of the same user are serialized on the user row
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE id = @user_id FOR UPDATE`, args).Scan(&userId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return -1, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	// an imported entry is checked first, it would overlap itself on a re-import
+	if task.ExternalUid != nil {
+		var imported bool
+		query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE user_id = @user_id AND external_uid = @external_uid)`
+
+		if err := tx.QueryRow(ctx, query, args).Scan(&imported); err != nil {
+			return -1, fmt.Errorf("unable to select task: %w", err)
+		}
+
+		if imported {
+			return -1, storage.ErrAlreadyImported
+		}
+	}
+
+	if err := checkPeriodLocked(ctx, tx, userId, startTime, endTime); err != nil {
+		return -1, err
+	}
+
+	if !allowOverlap {
+		overlap, err := hasOverlap(ctx, tx, userId, startTime, endTime, 0)
+		if err != nil {
+			return -1, err
+		}
+
+		if overlap {
+			return -1, storage.ErrOverlap
+		}
+	}
+
+	var id int
+	if err := tx.QueryRow(ctx, insertTask, args).Scan(&id); err != nil {
+		return -1, insertTaskErr(err)
+	}
+
+	args["id"] = id
+
+	query := `
+	INSERT INTO task_sessions (task_id, started_at, stopped_at)
+	VALUES (@id, @started_at, @stopped_at)`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
 		return -1, fmt.Errorf("unable to insert row: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
 	return id, nil
 }
 
-func (pg *postgres) BeginTask(ctx context.Context, id int, startTime time.Time) error {
+// hasOverlap reports whether the user has a session intersecting start..end.
+// Open sessions are treated as running forever. The session with id exclude is ignored.
+func hasOverlap(ctx context.Context, tx pgx.Tx, userId int, start, end time.Time, exclude int) (bool, error) {
 	query := `
-	UPDATE tasks SET start_time = @start_time WHERE id = @id
-	`
+	SELECT EXISTS (
+		SELECT 1 FROM task_sessions
+		JOIN tasks ON tasks.id = task_sessions.task_id
+		WHERE tasks.user_id = @user_id AND task_sessions.id <> @exclude
+		AND started_at < @end AND COALESCE(stopped_at, 'infinity') > @start
+	)`
+
+	args := pgx.NamedArgs{
+		"user_id": userId,
+		"exclude": exclude,
+		"start":   start,
+		"end":     end,
+	}
+
+	var overlap bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&overlap); err != nil {
+		return false, fmt.Errorf("unable to check overlap: %w", err)
+	}
+
+	return overlap, nil
+}
+
+type TaskUpdate struct {
+	Description *string
+	Billable    *bool
+	// ParentId moves the task under another task of the same user, zero makes it a top-level task.
+	ParentId *int
+	// EstimateMinutes changes the planned duration, zero removes it. A new estimate
+	// clears the alerts raised for the old one.
+	EstimateMinutes *int
+	// SessionId selects the session whose times are changed, the latest one if zero.
+	SessionId    int
+	StartTime    *time.Time
+	EndTime      *time.Time
+	AllowOverlap bool
+}
+
+// UpdateTask changes the description of the task and the times of one of its sessions,
+// writing every changed field to the audit trail on behalf of changedBy.
+// Setting the end of an open session stops a running task.
+func (pg *postgres) UpdateTask(ctx context.Context, id int, changedBy int, upd TaskUpdate, now time.Time) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userId, err := lockTask(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	// a task with time in an approved timesheet is not changed at all
+	if err := checkTaskLocked(ctx, tx, id, now); err != nil {
+		return err
+	}
 
 	args := pgx.NamedArgs{
-		"start_time": startTime,
-		"id":       id,
+		"id":         id,
+		"session_id": upd.SessionId,
 	}
 
-	results, err := pg.db.Exec(ctx, query, args)
+	var (
+		description string
+		billable    bool
+		status      TaskStatus
+		parentId    *int
+		estimate    *int
+	)
 
-	if results.RowsAffected() == 0 {
-		return errors.New("not id delet")
+	query := `SELECT description, billable, status, parent_id, estimate_minutes FROM tasks WHERE id = @id`
+
+	err = tx.QueryRow(ctx, query, args).Scan(&description, &billable, &status, &parentId, &estimate)
+	if err != nil {
+		return fmt.Errorf("unable to select task: %w", err)
 	}
 
+	var records []AuditRecord
+
+	if upd.Description != nil && *upd.Description != description {
+		args["description"] = *upd.Description
+
+		if _, err := tx.Exec(ctx, `UPDATE tasks SET description = @description WHERE id = @id`, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+
+		records = append(records, auditChange(id, nil, changedBy, "description", description, *upd.Description))
+	}
+
+	if upd.Billable != nil && *upd.Billable != billable {
+		args["billable"] = *upd.Billable
+
+		if _, err := tx.Exec(ctx, `UPDATE tasks SET billable = @billable WHERE id = @id`, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+
+		records = append(records, auditChange(id, nil, changedBy, "billable", strconv.FormatBool(billable), strconv.FormatBool(*upd.Billable)))
+	}
+
+	if upd.ParentId != nil && formatId(parentId) != formatId(upd.ParentId) {
+		var newParentId *int
+
+		if *upd.ParentId != 0 {
+			if err := checkParent(ctx, tx, id, userId, *upd.ParentId); err != nil {
+				return err
+			}
+
+			newParentId = upd.ParentId
+		}
+
+		args["parent_id"] = newParentId
+
+		if _, err := tx.Exec(ctx, `UPDATE tasks SET parent_id = @parent_id WHERE id = @id`, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+
+		records = append(records, auditChange(id, nil, changedBy, "parent_id", formatId(parentId), formatId(newParentId)))
+	}
+
+	if upd.EstimateMinutes != nil && formatId(estimate) != formatId(upd.EstimateMinutes) {
+		var newEstimate *int
+		if *upd.EstimateMinutes != 0 {
+			newEstimate = upd.EstimateMinutes
+		}
+
+		args["estimate_minutes"] = newEstimate
+
+		query := `UPDATE tasks SET estimate_minutes = @estimate_minutes, estimate_reached = 0 WHERE id = @id`
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+
+		records = append(records, auditChange(id, nil, changedBy, "estimate_minutes", formatId(estimate), formatId(newEstimate)))
+	}
+
+	if upd.StartTime != nil || upd.EndTime != nil {
+		query := `
+		SELECT id, started_at, stopped_at FROM task_sessions
+		WHERE task_id = @id AND (id = @session_id OR @session_id = 0)
+		ORDER BY started_at DESC
+		LIMIT 1
+		FOR UPDATE
+		`
+
+		var (
+			sessionId int
+			startedAt time.Time
+			stoppedAt *time.Time
+		)
+
+		err := tx.QueryRow(ctx, query, args).Scan(&sessionId, &startedAt, &stoppedAt)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrSessionNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to select session: %w", err)
+		}
+
+		newStart, newStop := startedAt, stoppedAt
+		if upd.StartTime != nil {
+			newStart = *upd.StartTime
+		}
+		if upd.EndTime != nil {
+			newStop = upd.EndTime
+		}
+
+		// an open session is checked as if it was stopped now
+		end := now
+		if newStop != nil {
+			end = *newStop
+		}
+
+		if err := interval.Validate(newStart, end, now); err != nil {
+			return err
+		}
+
+		if err := checkPeriodLocked(ctx, tx, userId, newStart, end); err != nil {
+			return err
+		}
+
+		if !upd.AllowOverlap {
+			overlap, err := hasOverlap(ctx, tx, userId, newStart, end, sessionId)
+			if err != nil {
+				return err
+			}
+
+			if overlap {
+				return storage.ErrOverlap
+			}
+		}
+
+		args["session_id"] = sessionId
+		args["started_at"] = newStart
+		args["stopped_at"] = newStop
+
+		query = `UPDATE task_sessions SET started_at = @started_at, stopped_at = @stopped_at WHERE id = @session_id`
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+
+		if !newStart.Equal(startedAt) {
+			records = append(records, auditChange(id, &sessionId, changedBy, "start_time", formatTime(&startedAt), formatTime(&newStart)))
+		}
+
+		if stoppedAt == nil && newStop != nil {
+			records = append(records, auditChange(id, &sessionId, changedBy, "end_time", formatTime(stoppedAt), formatTime(newStop)))
+
+			if status == TaskRunning {
+				args["status"] = TaskPaused
+
+				if _, err := tx.Exec(ctx, `UPDATE tasks SET status = @status WHERE id = @id`, args); err != nil {
+					return fmt.Errorf("unable to update row: %w", err)
+				}
+
+				records = append(records, auditChange(id, nil, changedBy, "status", string(status), string(TaskPaused)))
+			}
+		} else if stoppedAt != nil && !stoppedAt.Equal(*newStop) {
+			records = append(records, auditChange(id, &sessionId, changedBy, "end_time", formatTime(stoppedAt), formatTime(newStop)))
+		}
+	}
+
+	if err := writeAudit(ctx, tx, records); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteTask removes the task with all its sessions, recording the deletion in the audit trail.
+func (pg *postgres) DeleteTask(ctx context.Context, id int, changedBy int) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockTask(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := checkTaskLocked(ctx, tx, id, time.Now()); err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	// the sessions are deleted with the task, the trail keeps their times as
+	// start/end intervals, the end is empty for a running session
+	rows, err := tx.Query(ctx, `SELECT id, started_at, stopped_at FROM task_sessions WHERE task_id = @id ORDER BY started_at`, args)
+	if err != nil {
+		return fmt.Errorf("unable to select task sessions: %w", err)
+	}
+
+	type session struct {
+		Id        int
+		StartedAt time.Time
+		StoppedAt *time.Time
+	}
+
+	sessions, err := pgx.CollectRows(rows, pgx.RowToStructByName[session])
 	if err != nil {
-		return fmt.Errorf("unable to insert row: %w", err)
+		return fmt.Errorf("unable to read task sessions: %w", err)
+	}
+
+	field := "session"
+	records := make([]AuditRecord, 0, len(sessions)+1)
+
+	for _, ses := range sessions {
+		times := formatTime(&ses.StartedAt) + "/" + formatTime(ses.StoppedAt)
+
+		records = append(records, AuditRecord{
+			TaskId:    id,
+			SessionId: &ses.Id,
+			ChangedBy: changedBy,
+			Action:    AuditDelete,
+			Field:     &field,
+			OldValue:  &times,
+		})
+	}
+
+	var description string
+	err = tx.QueryRow(ctx, `DELETE FROM tasks WHERE id = @id RETURNING description`, args).Scan(&description)
+	if err != nil {
+		return fmt.Errorf("unable to delete row: %w", err)
+	}
+
+	records = append(records, AuditRecord{
+		TaskId:    id,
+		ChangedBy: changedBy,
+		Action:    AuditDelete,
+		OldValue:  &description,
+	})
+
+	if err := writeAudit(ctx, tx, records); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockTask locks the owner of the task and then the task itself, so that changes
+// touching several tasks of a user are serialized, and returns the owner id.
+func lockTask(ctx context.Context, tx pgx.Tx, id int) (int, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var userId int
+	err := tx.QueryRow(ctx, `SELECT user_id FROM tasks WHERE id = @id`, args).Scan(&userId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, storage.ErrTaskNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to select task: %w", err)
+	}
+
+	args["user_id"] = userId
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = @user_id FOR UPDATE`, args); err != nil {
+		return 0, fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `SELECT id FROM tasks WHERE id = @id FOR UPDATE`, args).Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, storage.ErrTaskNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to lock task: %w", err)
+	}
+
+	return userId, nil
+}
+
+func auditChange(taskId int, sessionId *int, changedBy int, field, oldValue, newValue string) AuditRecord {
+	return AuditRecord{
+		TaskId:    taskId,
+		SessionId: sessionId,
+		ChangedBy: changedBy,
+		Action:    AuditUpdate,
+		Field:     &field,
+		OldValue:  &oldValue,
+		NewValue:  &newValue,
+	}
+}
+
+// checkParent makes sure parentId is a task of the user that is neither the task id
+// nor one of its subtasks, which would make a cycle.
+func checkParent(ctx context.Context, tx pgx.Tx, id, userId, parentId int) error {
+	query := `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id FROM tasks WHERE id = @parent_id AND user_id = @user_id
+		UNION ALL
+		SELECT tasks.id, tasks.parent_id FROM tasks
+		JOIN ancestors ON tasks.id = ancestors.parent_id
+	)
+	SELECT COUNT(*) > 0, COALESCE(bool_or(id = @id), false) FROM ancestors
+	`
+
+	args := pgx.NamedArgs{
+		"id":        id,
+		"user_id":   userId,
+		"parent_id": parentId,
+	}
+
+	var found, cycle bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&found, &cycle); err != nil {
+		return fmt.Errorf("unable to check parent: %w", err)
+	}
+
+	if !found {
+		return storage.ErrParentNotFound
+	}
+
+	if cycle {
+		return storage.ErrTaskCycle
 	}
 
 	return nil
 }
 
-func (pg *postgres) StopTask(ctx context.Context, id int, endTime time.Time) error {
+// formatId formats an optional id or number for the audit trail, zero and nil being empty.
+func formatId(id *int) string {
+	if id == nil || *id == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(*id)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+type TaskStatus string
+
+const (
+	TaskCreated TaskStatus = "created"
+	TaskRunning TaskStatus = "running"
+	TaskPaused  TaskStatus = "paused"
+	TaskDone    TaskStatus = "done"
+)
+
+// Reasons reported for illegal status transitions.
+const (
+	ReasonAlreadyRunning  = "task_already_running"
+	ReasonNotStarted      = "task_not_started"
+	ReasonNotRunning      = "task_not_running"
+	ReasonTaskDone        = "task_done"
+	ReasonStopBeforeStart = "stop_before_start"
+)
+
+// transition checks that a task in status from may move to status to.
+func transition(from, to TaskStatus) error {
+	reason := ""
+
+	switch {
+	case from == TaskDone:
+		reason = ReasonTaskDone
+	case to == TaskRunning && from == TaskRunning:
+		reason = ReasonAlreadyRunning
+	case to != TaskRunning && from == TaskCreated:
+		reason = ReasonNotStarted
+	case to == TaskPaused && from == TaskPaused:
+		reason = ReasonNotRunning
+	}
+
+	if reason != "" {
+		return &storage.TransitionError{Reason: reason, From: string(from), To: string(to)}
+	}
+
+	return nil
+}
+
+// BeginTask opens a new session of the task. If stopOthers is set, other running
+// tasks of the same user are paused in the same transaction and their ids returned.
+func (pg *postgres) BeginTask(ctx context.Context, id int, startTime time.Time, stopOthers bool) ([]int, error) {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"id":         id,
+		"started_at": startTime,
+		"status":     TaskRunning,
+		"paused":     TaskPaused,
+	}
+
+	userId, err := lockTask(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	args["user_id"] = userId
+
+	var status TaskStatus
+	err = tx.QueryRow(ctx, `SELECT status FROM tasks WHERE id = @id`, args).Scan(&status)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to select task: %w", err)
+	}
+
+	if err := transition(status, TaskRunning); err != nil {
+		return nil, err
+	}
+
+	if err := checkPeriodLocked(ctx, tx, userId, startTime, startTime); err != nil {
+		return nil, err
+	}
+
+	var stopped []int
+
+	if stopOthers {
+		query := `
+		SELECT id FROM tasks
+		WHERE user_id = @user_id AND status = @status AND id <> @id
+		FOR UPDATE
+		`
+
+		rows, err := tx.Query(ctx, query, args)
+		if err != nil {
+			return nil, fmt.Errorf("unable to select running tasks: %w", err)
+		}
+
+		stopped, err = pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return nil, fmt.Errorf("unable to select running tasks: %w", err)
+		}
+	}
+
+	if len(stopped) > 0 {
+		args["stopped"] = stopped
+
+		query := `
+		UPDATE task_sessions SET stopped_at = GREATEST(started_at, @started_at)
+		WHERE task_id = ANY(@stopped) AND stopped_at IS NULL
+		`
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return nil, fmt.Errorf("unable to stop running tasks: %w", err)
+		}
+
+		query = `UPDATE tasks SET status = @paused WHERE id = ANY(@stopped)`
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return nil, fmt.Errorf("unable to stop running tasks: %w", err)
+		}
+	}
+
+	query := `
+	INSERT INTO task_sessions (task_id, started_at) VALUES (@id, @started_at)
+	`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return nil, fmt.Errorf("unable to insert row: %w", err)
+	}
+
+	query = `UPDATE tasks SET status = @status WHERE id = @id`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return nil, fmt.Errorf("unable to update row: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return stopped, nil
+}
+
+// StopTask closes the open session of a running task and pauses it.
+// If done is set the task is finished instead and can not be started again.
+func (pg *postgres) StopTask(ctx context.Context, id int, endTime time.Time, done bool) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-	UPDATE tasks SET end_time = @end_time WHERE id = @id
+	SELECT user_id, status, (
+		SELECT started_at FROM task_sessions WHERE task_id = tasks.id AND stopped_at IS NULL
+	)
+	FROM tasks WHERE id = @id
+	FOR UPDATE
 	`
 
+	next := TaskPaused
+	if done {
+		next = TaskDone
+	}
+
 	args := pgx.NamedArgs{
-		"id":       id,
-		"end_time": endTime,
+		"id":         id,
+		"stopped_at": endTime,
+		"status":     next,
 	}
 
-	results, err := pg.db.Exec(ctx, query, args)
+	var (
+		userId    int
+		status    TaskStatus
+		startedAt *time.Time
+	)
+	err = tx.QueryRow(ctx, query, args).Scan(&userId, &status, &startedAt)
 
-	if results.RowsAffected() == 0 {
-		return errors.New("not id delet")
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrTaskNotFound
 	}
 
 	if err != nil {
-		return fmt.Errorf("unable to insert row: %w", err)
+		return fmt.Errorf("unable to select task: %w", err)
 	}
 
-	return nil
+	if err := transition(status, next); err != nil {
+		return err
+	}
+
+	if startedAt != nil {
+		if endTime.Before(*startedAt) {
+			return &storage.TransitionError{Reason: ReasonStopBeforeStart, From: string(status), To: string(next)}
+		}
+
+		if err := checkPeriodLocked(ctx, tx, userId, *startedAt, endTime); err != nil {
+			return err
+		}
+
+		query = `
+		UPDATE task_sessions SET stopped_at = @stopped_at
+		WHERE task_id = @id AND stopped_at IS NULL
+		`
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return fmt.Errorf("unable to update row: %w", err)
+		}
+	}
+
+	query = `UPDATE tasks SET status = @status WHERE id = @id`
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (pg *postgres) GetUserTaskTime(ctx context.Context, user_id int, startPeriod, endPeriod time.Time) ([]TaskTime, error) {
+// GetRunningTasks returns tasks of the user with an open session, durations are computed up to now.
+func (pg *postgres) GetRunningTasks(ctx context.Context, userId int, now time.Time) ([]RunningTask, error) {
 	query := `
-    SELECT tasks.id as task_id, 
-	EXTRACT(EPOCH FROM (end_time - start_time)) / 3600 AS hours, 
-	(EXTRACT(EPOCH FROM (end_time - start_time)) % 3600) / 60 AS minutes
-    FROM tasks
-	join users on tasks.user_id = users.id
-	WHERE user_id = @user_id AND @start_period < start_time AND end_time < @end_period
-	ORDER BY hours, minutes DESC
+	SELECT tasks.id AS task_id, tasks.description, task_sessions.started_at,
+	tasks.estimate_minutes, tasks.estimate_reached, ` + trackedSeconds + ` AS tracked_seconds
+	FROM tasks
+	JOIN task_sessions ON task_sessions.task_id = tasks.id AND task_sessions.stopped_at IS NULL
+	WHERE tasks.user_id = @user_id
+	ORDER BY task_sessions.started_at
 	`
+
 	args := pgx.NamedArgs{
-		"user_id":      user_id,
-		"start_period": startPeriod,
-		"end_period":   endPeriod,
+		"user_id": userId,
 	}
 
 	rows, err := pg.db.Query(ctx, query, args)
@@ -106,11 +826,18 @@ func (pg *postgres) GetUserTaskTime(ctx context.Context, user_id int, startPerio
 	}
 
 	defer rows.Close()
-	result, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskTime])
+	result, err := pgx.CollectRows(rows, pgx.RowToStructByName[RunningTask])
 
 	if err != nil {
 		return nil, err
 	}
 
-	return result, err
+	for i := range result {
+		result[i].ElapsedSeconds = max(now.Sub(result[i].StartedAt).Seconds(), 0)
+		result[i].TrackedSeconds += result[i].ElapsedSeconds
+		result[i].TotalSeconds = result[i].TrackedSeconds
+		result[i].fill()
+	}
+
+	return result, nil
 }
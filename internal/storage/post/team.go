@@ -0,0 +1,171 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TeamFilter struct {
+	// UserIds and Department select the users, a user matching either is included.
+	UserIds    []int
+	Department *string
+	// From and To are days, both included, counted in the time zone of each user.
+	From           time.Time
+	To             time.Time
+	IncludeRunning bool
+	Now            time.Time
+}
+
+// teamMembers selects the users of a team filter.
+const teamMembers = `members AS (
+		SELECT id, COALESCE(surname, '') AS surname, COALESCE(name, '') AS name, time_zone, part_time_factor
+		FROM users
+		WHERE id = ANY(@user_ids) OR department = @department
+	)`
+
+// TeamDay is the time of a user on one day.
+type TeamDay struct {
+	UserId  int
+	Surname string
+	Name    string
+	Day     time.Time
+	Seconds float64
+}
+
+// GetTeamTime returns the time of every selected user on every day of the period,
+// zero for days without sessions. Sessions crossing midnight of the user are split
+// between the days.
+func (pg *postgres) GetTeamTime(ctx context.Context, filter TeamFilter) ([]TeamDay, error) {
+	query := `
+	WITH ` + teamMembers + `, days AS (
+		SELECT members.id AS user_id, members.surname, members.name, day::date AS day,
+		day AT TIME ZONE members.time_zone AS day_start,
+		(day + interval '1 day') AT TIME ZONE members.time_zone AS day_end
+		FROM members,
+		generate_series(@from::date::timestamp, @to::date::timestamp, interval '1 day') AS day
+	)
+	SELECT days.user_id, days.surname, days.name, days.day,
+	COALESCE(SUM(EXTRACT(EPOCH FROM (
+		LEAST(COALESCE(task_sessions.stopped_at, @now), days.day_end)
+		- GREATEST(task_sessions.started_at, days.day_start)
+	))), 0) AS seconds
+	FROM days
+	LEFT JOIN tasks ON tasks.user_id = days.user_id
+	LEFT JOIN task_sessions ON task_sessions.task_id = tasks.id
+		AND task_sessions.started_at < days.day_end
+		AND COALESCE(task_sessions.stopped_at, @now) > days.day_start
+		AND (task_sessions.stopped_at IS NOT NULL OR @include_running)
+	GROUP BY days.user_id, days.surname, days.name, days.day
+	ORDER BY days.user_id, days.day
+	`
+
+	rows, err := pg.db.Query(ctx, query, filter.args())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[TeamDay])
+}
+
+func (filter TeamFilter) args() pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"user_ids":        intArray(filter.UserIds),
+		"department":      filter.Department,
+		"from":            filter.From,
+		"to":              filter.To,
+		"include_running": filter.IncludeRunning,
+		"now":             filter.Now,
+	}
+}
+
+// TeamSession is a session of a user of a team clipped to the period in the time
+// zone of the user. A user without sessions has a single row without a description.
+type TeamSession struct {
+	UserId      int
+	Surname     string
+	Name        string
+	TimeZone    string
+	Description *string
+	ProjectId   *int
+	StartedAt   time.Time
+	StoppedAt   time.Time
+}
+
+// GetTeamSessions returns the sessions of every selected user in the period,
+// ordered by user and start.
+func (pg *postgres) GetTeamSessions(ctx context.Context, filter TeamFilter) ([]TeamSession, error) {
+	query := `
+	WITH ` + teamMembers + `, periods AS (
+		SELECT members.*,
+		@from::date::timestamp AT TIME ZONE members.time_zone AS period_start,
+		(@to::date + 1)::timestamp AT TIME ZONE members.time_zone AS period_end
+		FROM members
+	)
+	SELECT periods.id AS user_id, periods.surname, periods.name, periods.time_zone,
+	sessions.description, sessions.project_id,
+	GREATEST(sessions.started_at, periods.period_start) AS started_at,
+	LEAST(COALESCE(sessions.stopped_at, @now), periods.period_end) AS stopped_at
+	FROM periods
+	LEFT JOIN (
+		SELECT tasks.user_id, tasks.description, tasks.project_id,
+		task_sessions.started_at, task_sessions.stopped_at
+		FROM tasks
+		JOIN task_sessions ON task_sessions.task_id = tasks.id
+	) sessions ON sessions.user_id = periods.id
+		AND sessions.started_at < periods.period_end
+		AND COALESCE(sessions.stopped_at, @now) > periods.period_start
+		AND (sessions.stopped_at IS NOT NULL OR @include_running)
+	ORDER BY periods.id, sessions.started_at
+	`
+
+	rows, err := pg.db.Query(ctx, query, filter.args())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[TeamSession])
+}
+
+// TeamAbsence is an approved absence of a user of a team with the part-time factor
+// of the user.
+type TeamAbsence struct {
+	Absence
+	PartTimeFactor float64
+}
+
+// GetTeamAbsences returns the approved absences of every selected user intersecting
+// the days of the period, ordered by user and start.
+func (pg *postgres) GetTeamAbsences(ctx context.Context, filter TeamFilter) ([]TeamAbsence, error) {
+	query := `
+	WITH ` + teamMembers + `
+	SELECT absences.id, absences.user_id, absences.type, absences.start_date, absences.end_date,
+	absences.half_day, absences.status, absences.comment, absences.created_at, absences.reviewed_by,
+	absences.reviewed_at, absences.review_comment, members.part_time_factor
+	FROM absences
+	JOIN members ON members.id = absences.user_id
+	WHERE absences.status = @approved
+	AND absences.start_date <= @to::date AND absences.end_date > @from::date
+	ORDER BY absences.user_id, absences.start_date
+	`
+
+	args := filter.args()
+	args["approved"] = AbsenceApproved
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[TeamAbsence])
+}
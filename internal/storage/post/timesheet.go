@@ -0,0 +1,330 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"time_tracker/internal/storage"
+)
+
+type TimesheetStatus string
+
+const (
+	TimesheetSubmitted TimesheetStatus = "submitted"
+	TimesheetApproved  TimesheetStatus = "approved"
+	TimesheetRejected  TimesheetStatus = "rejected"
+)
+
+// Timesheet is a week or a month of a user submitted for approval. PeriodStart and
+// PeriodEnd are days in the time zone of the user, PeriodEnd is the first day after
+// the period. LockedFrom and LockedTo are the instants of the period in the time zone
+// of the user when it was submitted.
+type Timesheet struct {
+	Id          int             `json:"id"`
+	UserId      int             `json:"user_id"`
+	Period      string          `json:"period"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"`
+	LockedFrom  time.Time       `json:"locked_from"`
+	LockedTo    time.Time       `json:"locked_to"`
+	Status      TimesheetStatus `json:"status"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	ReviewedBy  *int            `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time      `json:"reviewed_at,omitempty"`
+	Comment     *string         `json:"comment,omitempty"`
+}
+
+// checkPeriodLocked returns ErrPeriodLocked if start..end intersects an approved
+// timesheet of the user. An instant, start equal to end, is locked if it is inside
+// a period.
+func checkPeriodLocked(ctx context.Context, tx pgx.Tx, userId int, start, end time.Time) error {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM timesheets
+		WHERE user_id = @user_id AND status = @approved
+		AND (locked_from <= @start AND locked_to > @start OR locked_from < @end AND locked_to > @start)
+	)`
+
+	args := pgx.NamedArgs{
+		"user_id":  userId,
+		"approved": TimesheetApproved,
+		"start":    start,
+		"end":      end,
+	}
+
+	var locked bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked); err != nil {
+		return fmt.Errorf("unable to check locked periods: %w", err)
+	}
+
+	if locked {
+		return storage.ErrPeriodLocked
+	}
+
+	return nil
+}
+
+// checkTaskLocked returns ErrPeriodLocked if a session of the task intersects an
+// approved timesheet of its user, open sessions lasting until now.
+func checkTaskLocked(ctx context.Context, tx pgx.Tx, taskId int, now time.Time) error {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM task_sessions
+		JOIN tasks ON tasks.id = task_sessions.task_id
+		JOIN timesheets ON timesheets.user_id = tasks.user_id AND timesheets.status = @approved
+		WHERE task_sessions.task_id = @task_id
+		AND started_at < locked_to AND (COALESCE(stopped_at, @now) > locked_from OR started_at >= locked_from)
+	)`
+
+	args := pgx.NamedArgs{
+		"task_id":  taskId,
+		"approved": TimesheetApproved,
+		"now":      now,
+	}
+
+	var locked bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked); err != nil {
+		return fmt.Errorf("unable to check locked periods: %w", err)
+	}
+
+	if locked {
+		return storage.ErrPeriodLocked
+	}
+
+	return nil
+}
+
+// checkTimerRunning returns ErrTimerRunning if the user has an open session started
+// before lockedTo, the end of the period, its time in the period is not known yet.
+func checkTimerRunning(ctx context.Context, tx pgx.Tx, userId int, lockedTo time.Time) error {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM task_sessions
+		JOIN tasks ON tasks.id = task_sessions.task_id
+		WHERE tasks.user_id = @user_id AND stopped_at IS NULL AND started_at < @locked_to
+	)`
+
+	args := pgx.NamedArgs{
+		"user_id":   userId,
+		"locked_to": lockedTo,
+	}
+
+	var running bool
+	if err := tx.QueryRow(ctx, query, args).Scan(&running); err != nil {
+		return fmt.Errorf("unable to check running timers: %w", err)
+	}
+
+	if running {
+		return storage.ErrTimerRunning
+	}
+
+	return nil
+}
+
+// SubmitTimesheet submits the period of the user for approval. A rejected timesheet
+// of the same period is submitted again. The period must not overlap other timesheets
+// that are not rejected, and no timer of the user may be running in it. The instants
+// it locks once approved are taken in the current time zone of the user.
+func (pg *postgres) SubmitTimesheet(ctx context.Context, userId int, period string, start, end time.Time, now time.Time) (int, error) {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"user_id":      userId,
+		"period":       period,
+		"period_start": start,
+		"period_end":   end,
+		"submitted":    TimesheetSubmitted,
+		"rejected":     TimesheetRejected,
+		"approved":     TimesheetApproved,
+		"now":          now,
+	}
+
+	var lockedFrom, lockedTo time.Time
+
+	// timesheets and time entries of the same user are serialized on the user row
+	query := `
+	SELECT id, @period_start::date::timestamp AT TIME ZONE time_zone, @period_end::date::timestamp AT TIME ZONE time_zone
+	FROM users WHERE id = @user_id FOR UPDATE`
+
+	err = tx.QueryRow(ctx, query, args).Scan(&userId, &lockedFrom, &lockedTo)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return -1, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	query = `
+	SELECT COUNT(*), COALESCE(bool_or(period_start <> @period_start::date OR period_end <> @period_end::date), false),
+	COALESCE(bool_or(status = @approved), false)
+	FROM timesheets
+	WHERE user_id = @user_id AND status <> @rejected
+	AND period_start < @period_end::date AND period_end > @period_start::date
+	`
+
+	var (
+		count             int
+		overlap, approved bool
+	)
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&count, &overlap, &approved); err != nil {
+		return -1, fmt.Errorf("unable to select timesheets: %w", err)
+	}
+
+	switch {
+	case overlap:
+		return -1, storage.ErrTimesheetOverlap
+	case approved:
+		return -1, storage.ErrTimesheetApproved
+	case count > 0:
+		return -1, storage.ErrTimesheetPending
+	}
+
+	if err := checkTimerRunning(ctx, tx, userId, lockedTo); err != nil {
+		return -1, err
+	}
+
+	args["locked_from"], args["locked_to"] = lockedFrom, lockedTo
+
+	query = `
+	INSERT INTO timesheets (user_id, period, period_start, period_end, locked_from, locked_to, status, submitted_at)
+	VALUES (@user_id, @period, @period_start, @period_end, @locked_from, @locked_to, @submitted, @now)
+	ON CONFLICT (user_id, period_start, period_end) DO UPDATE SET
+	period = EXCLUDED.period, locked_from = EXCLUDED.locked_from, locked_to = EXCLUDED.locked_to,
+	status = EXCLUDED.status, submitted_at = EXCLUDED.submitted_at,
+	reviewed_by = NULL, reviewed_at = NULL, comment = NULL
+	RETURNING id`
+
+	var id int
+	if err := tx.QueryRow(ctx, query, args).Scan(&id); err != nil {
+		return -1, fmt.Errorf("unable to insert row: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// ReviewTimesheet approves or rejects a submitted timesheet on behalf of reviewedBy.
+// An approved period is locked: time entries in it can not be started, stopped,
+// created, changed or deleted.
+func (pg *postgres) ReviewTimesheet(ctx context.Context, id int, reviewedBy int, approve bool, comment *string, now time.Time) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status := TimesheetRejected
+	if approve {
+		status = TimesheetApproved
+	}
+
+	args := pgx.NamedArgs{
+		"id":          id,
+		"reviewed_by": reviewedBy,
+		"status":      status,
+		"comment":     comment,
+		"now":         now,
+	}
+
+	var userId int
+	err = tx.QueryRow(ctx, `SELECT user_id FROM timesheets WHERE id = @id`, args).Scan(&userId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrTimesheetNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to select timesheet: %w", err)
+	}
+
+	args["user_id"] = userId
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = @user_id FOR UPDATE`, args); err != nil {
+		return fmt.Errorf("unable to lock user: %w", err)
+	}
+
+	var (
+		current  TimesheetStatus
+		lockedTo time.Time
+	)
+
+	query := `SELECT status, locked_to FROM timesheets WHERE id = @id FOR UPDATE`
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&current, &lockedTo); err != nil {
+		return fmt.Errorf("unable to lock timesheet: %w", err)
+	}
+
+	if current != TimesheetSubmitted {
+		return storage.ErrNotSubmitted
+	}
+
+	if reviewedBy == userId {
+		return storage.ErrOwnTimesheet
+	}
+
+	if approve {
+		if err := checkTimerRunning(ctx, tx, userId, lockedTo); err != nil {
+			return err
+		}
+	}
+
+	query = `
+	UPDATE timesheets SET status = @status, reviewed_by = @reviewed_by, reviewed_at = @now, comment = @comment
+	WHERE id = @id
+	`
+
+	_, err = tx.Exec(ctx, query, args)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTimesheets returns the timesheets of the user, or of all users, optionally with
+// the status only, the latest periods first.
+func (pg *postgres) GetTimesheets(ctx context.Context, userId *int, status *TimesheetStatus) ([]Timesheet, error) {
+	query := `
+	SELECT id, user_id, period, period_start, period_end, locked_from, locked_to, status, submitted_at,
+	reviewed_by, reviewed_at, comment
+	FROM timesheets
+	WHERE (user_id = @user_id OR @user_id::int IS NULL) AND (status = @status OR @status::text IS NULL)
+	ORDER BY period_start DESC, user_id
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userId,
+		"status":  status,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Timesheet])
+}
@@ -0,0 +1,67 @@
+package post
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
+)
+
+type TaskNode struct {
+	TaskId      int        `json:"task_id"`
+	ParentId    *int       `json:"parent_id,omitempty"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	// Seconds is the time of the finished sessions of the task itself.
+	Seconds float64 `json:"-"`
+	Estimate
+}
+
+// GetTaskTree returns the task with all its subtasks, parents before their children.
+func (pg *postgres) GetTaskTree(ctx context.Context, id int) ([]TaskNode, error) {
+	query := `
+	WITH RECURSIVE tree AS (
+		SELECT id, 0 AS depth FROM tasks WHERE id = @id
+		UNION ALL
+		SELECT tasks.id, tree.depth + 1 FROM tasks
+		JOIN tree ON tasks.parent_id = tree.id
+	)
+	SELECT tasks.id AS task_id, tasks.parent_id, tasks.description, tasks.status,
+	COALESCE(SUM(EXTRACT(EPOCH FROM (stopped_at - started_at))), 0) AS seconds,
+	tasks.estimate_minutes, tasks.estimate_reached, ` + trackedSeconds + ` AS tracked_seconds
+	FROM tree
+	JOIN tasks ON tasks.id = tree.id
+	LEFT JOIN task_sessions ON task_sessions.task_id = tasks.id AND stopped_at IS NOT NULL
+	GROUP BY tasks.id, tree.depth
+	ORDER BY tree.depth, tasks.id
+	`
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := pg.db.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	nodes, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskNode])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, storage.ErrTaskNotFound
+	}
+
+	for i := range nodes {
+		nodes[i].fill()
+	}
+
+	return nodes, nil
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+
+	"time_tracker/internal/storage"
 )
 
 type User struct {
@@ -46,7 +49,7 @@ func (pg *postgres) CreateUser(ctx context.Context, passportSerie, passportNumbe
 
 func (pg *postgres) GetUser(ctx context.Context, id *int, passportSerie, passportNumber *int, surname, name, patronymic *string, address *string, offset, limit *int) ([]User, error) {
 	query := `
-	select *
+	select id, passport_serie, passport_number, surname, name, patronymic, address
 	from users `
 
 	if id != nil || passportNumber != nil || passportSerie != nil || surname != nil || name != nil || patronymic != nil || address != nil{
@@ -179,3 +182,141 @@ func withAnd(query string, count int) string{
 	}
 	return query
 }
+
+type UserSettings struct {
+	// EndOfDay is a HH:MM time after which running timers of the user are stopped,
+	// an empty string removes it.
+	EndOfDay *string
+	// TimeZone is an IANA time zone name that report days of the user are counted in.
+	TimeZone *string
+	// Department groups users in team reports, an empty string removes it.
+	Department *string
+	// PartTimeFactor is the share of the standard working hours the user works, 1 for full time.
+	PartTimeFactor *float64
+}
+
+// UpdateUserSettings changes the settings that are set, leaving the others as is.
+func (pg *postgres) UpdateUserSettings(ctx context.Context, id int, settings UserSettings) error {
+	// every query converting days of users fails on a time zone Postgres does not know
+	if settings.TimeZone != nil {
+		args := pgx.NamedArgs{
+			"time_zone": *settings.TimeZone,
+		}
+
+		var known bool
+		err := pg.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = @time_zone)`, args).Scan(&known)
+
+		if err != nil {
+			return fmt.Errorf("unable to check time zone: %w", err)
+		}
+
+		if !known {
+			return storage.ErrInvalidTimeZone
+		}
+	}
+
+	query := `
+	UPDATE users SET
+	end_of_day = CASE WHEN @set_end_of_day THEN NULLIF(@end_of_day, '')::time ELSE end_of_day END,
+	time_zone = COALESCE(@time_zone, time_zone),
+	department = CASE WHEN @set_department THEN NULLIF(@department, '') ELSE department END,
+	part_time_factor = COALESCE(@part_time_factor, part_time_factor)
+	WHERE id = @id
+	`
+
+	args := pgx.NamedArgs{
+		"id":               id,
+		"set_end_of_day":   settings.EndOfDay != nil,
+		"end_of_day":       settings.EndOfDay,
+		"time_zone":        settings.TimeZone,
+		"set_department":   settings.Department != nil,
+		"department":       settings.Department,
+		"part_time_factor": settings.PartTimeFactor,
+	}
+
+	results, err := pg.db.Exec(ctx, query, args)
+
+	if err != nil {
+		return fmt.Errorf("unable to update row: %w", err)
+	}
+
+	if results.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// GetUserLocation returns the time zone of the user.
+func (pg *postgres) GetUserLocation(ctx context.Context, id int) (*time.Location, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var timeZone string
+	err := pg.db.QueryRow(ctx, `SELECT time_zone FROM users WHERE id = @id`, args).Scan(&timeZone)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to select user: %w", err)
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone of user %d: %w", id, err)
+	}
+
+	return loc, nil
+}
+
+// GetUserById returns the user with the id.
+func (pg *postgres) GetUserById(ctx context.Context, id int) (User, error) {
+	query := `
+	SELECT id, passport_serie, passport_number, COALESCE(surname, ''), COALESCE(name, ''),
+	COALESCE(patronymic, ''), COALESCE(address, '')
+	FROM users WHERE id = @id
+	`
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var user User
+	err := pg.db.QueryRow(ctx, query, args).Scan(
+		&user.Id, &user.PassportSerie, &user.PassportNumber,
+		&user.Surname, &user.Name, &user.Patronymic, &user.Address,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return User{}, fmt.Errorf("unable to select user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetPartTimeFactor returns the share of the standard working hours the user works.
+func (pg *postgres) GetPartTimeFactor(ctx context.Context, id int) (float64, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var factor float64
+	err := pg.db.QueryRow(ctx, `SELECT part_time_factor FROM users WHERE id = @id`, args).Scan(&factor)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to select user: %w", err)
+	}
+
+	return factor, nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrSessionNotFound   = errors.New("task session not found")
+	ErrProjectNotFound   = errors.New("project not found")
+	ErrParentNotFound    = errors.New("parent task not found")
+	ErrTaskCycle         = errors.New("task can not be a subtask of itself or its subtasks")
+	ErrProjectExists     = errors.New("project already exists")
+	ErrOverlap           = errors.New("time entry overlaps other entries of the user")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidSort       = errors.New("invalid sort field")
+	ErrIllegalTransition = errors.New("illegal task status transition")
+	ErrAlreadyImported   = errors.New("time entry is already imported")
+	ErrPeriodLocked      = errors.New("period is locked by an approved timesheet")
+	ErrTimerRunning      = errors.New("a timer of the user is running in the period")
+	ErrTimesheetNotFound = errors.New("timesheet not found")
+	ErrTimesheetOverlap  = errors.New("timesheet overlaps another timesheet of the user")
+	ErrTimesheetPending  = errors.New("timesheet is already submitted")
+	ErrTimesheetApproved = errors.New("timesheet is already approved")
+	ErrNotSubmitted      = errors.New("timesheet is not submitted")
+	ErrOwnTimesheet      = errors.New("timesheet can not be reviewed by its user")
+	ErrAbsenceNotFound   = errors.New("absence not found")
+	ErrAbsenceOverlap    = errors.New("absence overlaps another absence of the user")
+	ErrNotRequested      = errors.New("absence is not requested")
+	ErrOwnAbsence        = errors.New("absence can not be reviewed by its user")
+	ErrInvalidTimeZone   = errors.New("time zone is unknown to the database")
+)
+
+// TransitionError describes why a task can not change its status.
+// Reason is a machine-readable code returned to API clients.
+type TransitionError struct {
+	Reason string
+	From   string
+	To     string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s (%s)", ErrIllegalTransition, e.From, e.To, e.Reason)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}